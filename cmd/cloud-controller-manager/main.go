@@ -0,0 +1,91 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/cloud-provider/app"
+	"k8s.io/cloud-provider/app/config"
+	"k8s.io/cloud-provider/names"
+	"k8s.io/cloud-provider/options"
+	"k8s.io/component-base/cli"
+	cliflag "k8s.io/component-base/cli/flag"
+	_ "k8s.io/component-base/logs/json/register"          // register optional JSON log format
+	_ "k8s.io/component-base/metrics/prometheus/clientgo" // load all the prometheus client-go plugins
+	_ "k8s.io/component-base/metrics/prometheus/version"  // for version metric registration
+	"k8s.io/klog/v2"
+
+	carbidecloudprovider "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/cloudprovider"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/tracing"
+)
+
+func main() {
+	ccmOptions, err := options.NewCloudControllerManagerOptions()
+	if err != nil {
+		klog.Fatalf("unable to initialize command options: %v", err)
+	}
+
+	// Configure OpenTelemetry tracing from the standard OTEL_* environment variables
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		klog.Fatalf("unable to set up tracing: %v", err)
+	}
+
+	command := app.NewCloudControllerManagerCommand(ccmOptions, cloudInitializer, controllerInitializers(),
+		names.CCMControllerAliases(), cliflag.NamedFlagSets{}, wait.NeverStop)
+	code := cli.Run(command)
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		klog.Errorf("problem shutting down tracing: %v", err)
+	}
+	os.Exit(code)
+}
+
+// controllerInitializers returns the cloud controllers backed by InstancesV2.
+// The service and route controllers are left out as the provider has no load
+// balancers nor routes.
+func controllerInitializers() map[string]app.ControllerInitFuncConstructor {
+	controllerInitializers := map[string]app.ControllerInitFuncConstructor{}
+	for _, name := range []string{names.CloudNodeController, names.CloudNodeLifecycleController} {
+		if constructor, ok := app.DefaultInitFuncConstructors[name]; ok {
+			controllerInitializers[name] = constructor
+		}
+	}
+	return controllerInitializers
+}
+
+// cloudInitializer creates the cloud provider named by --cloud-provider from
+// the --cloud-config file
+func cloudInitializer(config *config.CompletedConfig) cloudprovider.Interface {
+	cloudConfig := config.ComponentConfig.KubeCloudShared.CloudProvider
+	if cloudConfig.Name != carbidecloudprovider.ProviderName {
+		klog.Fatalf("unsupported cloud provider %q, expected %q", cloudConfig.Name, carbidecloudprovider.ProviderName)
+	}
+
+	cloud, err := cloudprovider.InitCloudProvider(cloudConfig.Name, cloudConfig.CloudConfigFile)
+	if err != nil {
+		klog.Fatalf("Cloud provider could not be initialized: %v", err)
+	}
+	if cloud == nil {
+		klog.Fatalf("Cloud provider is nil")
+	}
+	return cloud
+}
@@ -17,21 +17,32 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+	ncpv1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
 	ncpv1beta1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1beta1"
 	machinecontroller "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/controllers/machine"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/metrics"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/tracing"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/webhooks"
 )
 
 var (
@@ -43,18 +54,58 @@ func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = machinev1beta1.AddToScheme(scheme)
 	_ = ncpv1beta1.AddToScheme(scheme)
+	_ = ncpv1.AddToScheme(scheme)
 }
 
 func main() {
 	var metricsAddr string
 	var probeAddr string
 	var enableLeaderElection bool
+	var defaultCredentialsSecret string
+	var credentialsNamespaces string
+	var enableMachineSetController bool
+	var enableCapacityController bool
+	var enableProviderIDMigration bool
+	var enableRemediation bool
+	var enableWebhooks bool
+	var providerDefaults string
+	var strictProviderSpec bool
+	var drainTimeout time.Duration
+	var drainSkipWaitForDeleteTimeout time.Duration
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&enableMachineSetController, "enable-machineset-controller", false,
+		"Enable the MachineSet controller. "+
+			"Only use this when the Machine API Operator does not already manage MachineSets.")
+	flag.BoolVar(&enableCapacityController, "enable-machineset-capacity-controller", false,
+		"Annotate MachineSets with their instance type capacity so the cluster-autoscaler can scale them from zero.")
+	flag.BoolVar(&enableProviderIDMigration, "enable-providerid-migration", false,
+		"Rewrite legacy Machine provider IDs without tenant to the tenant-aware format. "+
+			"Machines whose Node still has the legacy provider ID are left as is and the Node is flagged.")
+	flag.BoolVar(&enableRemediation, "enable-remediation", false,
+		"Remediate unhealthy Machines through NvidiaCarbideRemediation resources created by MachineHealthChecks. "+
+			"Requires the NvidiaCarbideRemediation CRDs.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the Machine and MachineSet admission webhooks. "+
+			"Requires a serving certificate in the webhook certificate directory.")
+	flag.StringVar(&providerDefaults, "provider-defaults", "",
+		"The namespace/name of a ConfigMap holding the siteId, tenantId, vpcId, credentialsSecret and "+
+			"clusterId defaults filled into provider specs by the defaulting webhook.")
+	flag.BoolVar(&strictProviderSpec, "strict-provider-spec", false,
+		"Fail instance creation when a provider spec has unknown or duplicate fields instead of reporting a warning.")
+	flag.DurationVar(&drainTimeout, "node-drain-timeout", 0,
+		"How long to wait for the pods of a Node to be evicted before deleting the instance anyway. 0 waits forever.")
+	flag.DurationVar(&drainSkipWaitForDeleteTimeout, "node-drain-skip-wait-for-delete-timeout",
+		machinecontroller.DefaultSkipWaitForDeleteTimeout,
+		"Ignore pods of a drained Node that were deleted longer ago than this.")
+	flag.StringVar(&defaultCredentialsSecret, "default-credentials-secret", "",
+		"The namespace/name of the credentials Secret used when a provider spec does not set credentialsSecret.")
+	flag.StringVar(&credentialsNamespaces, "credentials-namespaces", "openshift-machine-api",
+		"Comma-separated list of namespaces credentials Secrets may be read from. Empty allows all namespaces.")
 
 	opts := zap.Options{
 		Development: true,
@@ -64,8 +115,49 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	// Configure OpenTelemetry tracing from the standard OTEL_* environment variables
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "problem shutting down tracing")
+		}
+	}()
+
+	credentialsPolicy := machine.CredentialsPolicy{}
+	if defaultCredentialsSecret != "" {
+		key, err := machine.ParseCredentialsSecretKey(defaultCredentialsSecret)
+		if err != nil {
+			setupLog.Error(err, "invalid --default-credentials-secret")
+			os.Exit(1)
+		}
+		credentialsPolicy.DefaultSecret = &key
+	}
+	for _, ns := range strings.Split(credentialsNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			credentialsPolicy.AllowedNamespaces = append(credentialsPolicy.AllowedNamespaces, ns)
+		}
+	}
+
+	// Only cache Secrets from the allowed namespaces so that the RBAC can be
+	// limited to those namespaces
+	cacheOpts := cache.Options{}
+	if len(credentialsPolicy.AllowedNamespaces) > 0 {
+		secretNamespaces := map[string]cache.Config{}
+		for _, ns := range credentialsPolicy.AllowedNamespaces {
+			secretNamespaces[ns] = cache.Config{}
+		}
+		cacheOpts.ByObject = map[client.Object]cache.ByObject{
+			&corev1.Secret{}: {Namespaces: secretNamespaces},
+		}
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
+		Cache:  cacheOpts,
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
 		},
@@ -82,23 +174,78 @@ func main() {
 	actuator := machine.NewActuator(
 		mgr.GetClient(),
 		mgr.GetEventRecorderFor("nvidia-carbide-machine-controller"),
+		machine.WithCredentialsPolicy(credentialsPolicy),
+		machine.WithAPIReader(mgr.GetAPIReader()),
+		machine.WithStrictProviderSpec(strictProviderSpec),
 	)
 
 	// Setup Machine reconciler
-	if err = machinecontroller.SetupMachineController(mgr, actuator); err != nil {
+	if err = machinecontroller.SetupMachineController(mgr, actuator, machinecontroller.DrainOptions{
+		Timeout:                  drainTimeout,
+		SkipWaitForDeleteTimeout: drainSkipWaitForDeleteTimeout,
+	}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Machine")
 		os.Exit(1)
 	}
 
-	// Setup MachineSet reconciler (optional - can be enabled later)
-	// Uncomment the following lines to enable MachineSet controller:
-	// if err = (&machinecontroller.MachineSetReconciler{
-	// 	Client: mgr.GetClient(),
-	// 	Scheme: mgr.GetScheme(),
-	// }).SetupWithManager(mgr); err != nil {
-	// 	setupLog.Error(err, "unable to create controller", "controller", "MachineSet")
-	// 	os.Exit(1)
-	// }
+	// Setup MachineSet reconciler, for clusters without the Machine API
+	// Operator MachineSet controller
+	if enableMachineSetController {
+		if err = machinecontroller.SetupMachineSetController(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MachineSet")
+			os.Exit(1)
+		}
+	}
+
+	// Setup MachineSet capacity annotations for the cluster-autoscaler
+	if enableCapacityController {
+		if err = machinecontroller.SetupMachineSetCapacityController(mgr, actuator); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MachineSetCapacity")
+			os.Exit(1)
+		}
+	}
+
+	// Setup the legacy provider ID migration
+	if enableProviderIDMigration {
+		if err = machinecontroller.SetupProviderIDMigrationController(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ProviderIDMigration")
+			os.Exit(1)
+		}
+	}
+
+	// Setup external remediation for MachineHealthChecks
+	if enableRemediation {
+		if err = machinecontroller.SetupRemediationController(mgr, actuator); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Remediation")
+			os.Exit(1)
+		}
+	}
+
+	// Setup admission webhooks
+	if enableWebhooks {
+		defaulter := &webhooks.ProviderSpecDefaulter{
+			Reader:            mgr.GetAPIReader(),
+			CredentialsSecret: credentialsPolicy.DefaultSecret,
+		}
+		if providerDefaults != "" {
+			key, err := machine.ParseObjectKey(providerDefaults)
+			if err != nil {
+				setupLog.Error(err, "invalid --provider-defaults")
+				os.Exit(1)
+			}
+			defaulter.ConfigMap = &key
+		}
+		if err = webhooks.SetupWebhooks(mgr, defaulter); err != nil {
+			setupLog.Error(err, "unable to create webhooks")
+			os.Exit(1)
+		}
+	}
+
+	// Register Machine phase and instance state gauges
+	if err := ctrlmetrics.Registry.Register(metrics.NewMachineCollector(mgr.GetClient(), machine.IsOwnProviderSpec)); err != nil {
+		setupLog.Error(err, "unable to register machine metrics collector")
+		os.Exit(1)
+	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -108,6 +255,12 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if enableWebhooks {
+		if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			setupLog.Error(err, "unable to set up webhook ready check")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
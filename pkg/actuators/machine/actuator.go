@@ -19,20 +19,29 @@ package machine
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/google/uuid"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	kjson "sigs.k8s.io/json"
 
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
 	v1beta1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1beta1"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/metrics"
 	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/providerid"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/tracing"
 	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
 )
 
@@ -41,81 +50,96 @@ type NvidiaCarbideClientInterface interface {
 	CreateInstance(ctx context.Context, org string, req bmm.InstanceCreateRequest) (*bmm.Instance, *http.Response, error)
 	GetInstance(ctx context.Context, org string, instanceId string) (*bmm.Instance, *http.Response, error)
 	DeleteInstance(ctx context.Context, org string, instanceId string) (*http.Response, error)
+	GetInstanceType(ctx context.Context, org string, instanceTypeId string) (*bmm.InstanceType, *http.Response, error)
+	GetSite(ctx context.Context, org string, siteId string) (*bmm.Site, *http.Response, error)
+	GetVpc(ctx context.Context, org string, vpcId string) (*bmm.VPC, *http.Response, error)
+	GetSubnet(ctx context.Context, org string, subnetId string) (*bmm.Subnet, *http.Response, error)
+	GetSSHKeyGroup(ctx context.Context, org string, sshKeyGroupId string) (*bmm.SshKeyGroup, *http.Response, error)
+	RebootInstance(ctx context.Context, org string, instanceId string) (*http.Response, error)
+	UpdateInstance(
+		ctx context.Context, org string, instanceId string, req bmm.InstanceUpdateRequest,
+	) (*bmm.Instance, *http.Response, error)
 }
 
-// carbideClient wraps the SDK APIClient and injects auth context
-type carbideClient struct {
-	client *bmm.APIClient
-	token  string
-}
-
-func (c *carbideClient) authCtx(ctx context.Context) context.Context {
-	return context.WithValue(ctx, bmm.ContextAccessToken, c.token)
+// Actuator implements the OpenShift Machine actuator interface
+type Actuator struct {
+	client            client.Client
+	eventRecorder     record.EventRecorder
+	credentialsPolicy CredentialsPolicy
+	// reader bypasses the cache when re-reading rotated credentials
+	reader client.Reader
+	// preflight caches the Carbide resources referenced by provider specs
+	preflight *preflightCache
+	// strictProviderSpec fails creates on unknown or duplicate provider spec fields
+	strictProviderSpec bool
+	// For testing
+	nvidiaCarbideClient NvidiaCarbideClientInterface
+	orgName             string
 }
 
-func (c *carbideClient) CreateInstance(
-	ctx context.Context, org string, req bmm.InstanceCreateRequest,
-) (*bmm.Instance, *http.Response, error) {
-	return c.client.InstanceAPI.CreateInstance(c.authCtx(ctx), org).InstanceCreateRequest(req).Execute()
-}
+// ActuatorOption configures optional Actuator behaviour
+type ActuatorOption func(*Actuator)
 
-func (c *carbideClient) GetInstance(
-	ctx context.Context, org, instanceId string,
-) (*bmm.Instance, *http.Response, error) {
-	return c.client.InstanceAPI.GetInstance(c.authCtx(ctx), org, instanceId).Execute()
+// WithCredentialsPolicy sets the default credentials Secret and the namespaces
+// credentials may be read from
+func WithCredentialsPolicy(policy CredentialsPolicy) ActuatorOption {
+	return func(a *Actuator) {
+		a.credentialsPolicy = policy
+	}
 }
 
-func (c *carbideClient) DeleteInstance(ctx context.Context, org, instanceId string) (*http.Response, error) {
-	return c.client.InstanceAPI.DeleteInstance(c.authCtx(ctx), org, instanceId).Execute()
+// WithAPIReader sets the uncached reader used to re-read credentials Secrets
+// after Carbide rejects a token
+func WithAPIReader(reader client.Reader) ActuatorOption {
+	return func(a *Actuator) {
+		a.reader = reader
+	}
 }
 
-// Actuator implements the OpenShift Machine actuator interface
-type Actuator struct {
-	client        client.Client
-	eventRecorder record.EventRecorder
-	// For testing
-	nvidiaCarbideClient NvidiaCarbideClientInterface
-	orgName             string
+// WithStrictProviderSpec makes Create fail instead of warning when a provider
+// spec has unknown or duplicate fields
+func WithStrictProviderSpec(strict bool) ActuatorOption {
+	return func(a *Actuator) {
+		a.strictProviderSpec = strict
+	}
 }
 
 // NewActuator creates a new machine actuator
-func NewActuator(k8sClient client.Client, eventRecorder record.EventRecorder) *Actuator {
-	return &Actuator{
+func NewActuator(k8sClient client.Client, eventRecorder record.EventRecorder, opts ...ActuatorOption) *Actuator {
+	a := &Actuator{
 		client:        k8sClient,
 		eventRecorder: eventRecorder,
+		preflight:     newPreflightCache(preflightCacheTTL),
+	}
+	for _, opt := range opts {
+		opt(a)
 	}
+	return a
 }
 
 // NewActuatorWithClient creates a new machine actuator with injected client (for testing)
 func NewActuatorWithClient(
 	k8sClient client.Client, eventRecorder record.EventRecorder,
 	nvidiaCarbideClient NvidiaCarbideClientInterface, orgName string,
+	opts ...ActuatorOption,
 ) *Actuator {
-	return &Actuator{
-		client:              k8sClient,
-		eventRecorder:       eventRecorder,
-		nvidiaCarbideClient: nvidiaCarbideClient,
-		orgName:             orgName,
-	}
+	a := NewActuator(k8sClient, eventRecorder, opts...)
+	a.nvidiaCarbideClient = nvidiaCarbideClient
+	a.orgName = orgName
+	return a
 }
 
 // buildInstanceRequest constructs the API request body from a provider spec.
 func buildInstanceRequest(
 	name string,
-	providerSpec *v1beta1.NvidiaCarbideMachineProviderSpec,
+	providerSpec *v1.NvidiaCarbideMachineProviderSpec,
 ) bmm.InstanceCreateRequest {
-	interfaces := []bmm.InterfaceCreateRequest{
-		{
-			SubnetId:   &providerSpec.SubnetID,
-			IsPhysical: ptr(false),
-		},
-	}
-
-	for _, additionalSubnet := range providerSpec.AdditionalSubnetIDs {
-		subnetID := additionalSubnet.SubnetID
+	interfaces := make([]bmm.InterfaceCreateRequest, 0, len(providerSpec.Interfaces))
+	for _, iface := range providerSpec.Interfaces {
+		subnetID := iface.SubnetID
 		interfaces = append(interfaces, bmm.InterfaceCreateRequest{
 			SubnetId:   &subnetID,
-			IsPhysical: ptr(additionalSubnet.IsPhysical),
+			IsPhysical: ptr(iface.IsPhysical),
 		})
 	}
 
@@ -136,13 +160,18 @@ func buildInstanceRequest(
 	if providerSpec.AllowUnhealthyMachine {
 		req.AllowUnhealthyMachine = ptr(true)
 	}
-	if providerSpec.UserData != "" {
-		userData := providerSpec.UserData
-		req.UserData = *bmm.NewNullableString(&userData)
+	bootSource := providerSpec.BootSource
+	if bootSource.UserData != "" {
+		req.UserData = *bmm.NewNullableString(&bootSource.UserData)
 	}
-	// The API requires either ipxeScript or operatingSystemId.
-	// Provide a minimal iPXE script to satisfy the requirement.
-	{
+	switch {
+	case bootSource.OperatingSystemID != "":
+		req.OperatingSystemId = *bmm.NewNullableString(&bootSource.OperatingSystemID)
+	case bootSource.IPXEScript != "":
+		req.IpxeScript = *bmm.NewNullableString(&bootSource.IPXEScript)
+	default:
+		// The API requires either ipxeScript or operatingSystemId.
+		// Provide a minimal iPXE script to satisfy the requirement.
 		ipxeScript := "#!ipxe\necho Booting via Carbide"
 		req.IpxeScript = *bmm.NewNullableString(&ipxeScript)
 	}
@@ -157,7 +186,9 @@ func buildInstanceRequest(
 }
 
 // Create provisions a new instance
-func (a *Actuator) Create(ctx context.Context, machine runtime.Object) error {
+func (a *Actuator) Create(ctx context.Context, machine runtime.Object) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "Actuator.Create", machineAttributes(machine)...)
+	defer func() { tracing.EndSpan(span, err) }()
 	machineObj, ok := machine.(client.Object)
 	if !ok {
 		return fmt.Errorf("machine is not a client.Object")
@@ -166,21 +197,59 @@ func (a *Actuator) Create(ctx context.Context, machine runtime.Object) error {
 	// Parse provider spec
 	providerSpec, err := a.getProviderSpec(machineObj)
 	if err != nil {
-		return fmt.Errorf("failed to get provider spec: %w", err)
+		err = fmt.Errorf("failed to get provider spec: %w", err)
+		metrics.RecordCreate(err)
+		return err
+	}
+
+	// Misspelled fields are otherwise silently ignored
+	fieldProblems, err := a.checkProviderSpecFields(ctx, machineObj)
+	if err != nil {
+		metrics.RecordCreate(err)
+		return err
 	}
 
 	// Get NVIDIA Carbide client and orgName
-	nvidiaCarbideClient, orgName, err := a.getNvidiaCarbideClient(ctx, providerSpec)
+	nvidiaCarbideClient, orgName, err := a.getNvidiaCarbideClient(ctx, machineObj, providerSpec)
 	if err != nil {
-		return fmt.Errorf("failed to create NVIDIA Carbide client: %w", err)
+		err = fmt.Errorf("failed to create NVIDIA Carbide client: %w", err)
+		metrics.RecordCreate(err)
+		return err
+	}
+
+	// Resolve the referenced Carbide resources instead of letting the create
+	// fail with an opaque error. Lookups are cached per credentials Secret.
+	secretKey, err := a.credentialsPolicy.resolve(machineObj.GetNamespace(), providerSpec.CredentialsSecret)
+	if err != nil {
+		metrics.RecordCreate(err)
+		return err
+	}
+	if err := a.checkReferences(ctx, nvidiaCarbideClient, orgName, secretKey, providerSpec); err != nil {
+		metrics.RecordCreate(err)
+		a.reportCredentialsRejected(ctx, machineObj, err)
+		a.reportInvalidConfiguration(ctx, machineObj, err)
+		return err
+	}
+
+	// Fail early when the site has no free host of the instance type
+	if err := a.checkCapacity(ctx, nvidiaCarbideClient, orgName, providerSpec); err != nil {
+		metrics.RecordCreate(err)
+		a.reportCredentialsRejected(ctx, machineObj, err)
+		a.reportInsufficientCapacity(ctx, machineObj, err)
+		return err
 	}
 
 	// Build instance request
 	instanceReq := buildInstanceRequest(machineObj.GetName(), providerSpec)
+	if labels := deletionProtectionLabels(instanceReq.Labels, deletionProtected(machineObj, providerSpec)); labels != nil {
+		instanceReq.Labels = labels
+	}
 
 	// Create instance
 	instance, httpResp, err := nvidiaCarbideClient.CreateInstance(ctx, orgName, instanceReq)
 	if err != nil {
+		metrics.RecordCreate(err)
+		a.reportCredentialsRejected(ctx, machineObj, err)
 		if a.eventRecorder != nil {
 			a.eventRecorder.Eventf(machineObj, corev1.EventTypeWarning, "FailedCreate", "Failed to create instance: %v", err)
 		}
@@ -192,16 +261,29 @@ func (a *Actuator) Create(ctx context.Context, machine runtime.Object) error {
 	}
 
 	if instance == nil {
+		err := fmt.Errorf("create instance returned no data, status code: %d", httpResp.StatusCode)
+		metrics.RecordCreate(err)
 		if a.eventRecorder != nil {
 			a.eventRecorder.Eventf(machineObj, corev1.EventTypeWarning, "FailedCreate", "Create instance returned no data")
 		}
-		return fmt.Errorf("create instance returned no data, status code: %d", httpResp.StatusCode)
+		return err
 	}
+	metrics.RecordCreate(nil)
 
-	// Build provider status
-	providerStatus := &v1beta1.NvidiaCarbideMachineProviderStatus{
-		InstanceID: instance.Id,
+	// Build provider status, keeping previously reported conditions
+	previousStatus, err := a.getProviderStatus(machineObj)
+	if err != nil {
+		return fmt.Errorf("failed to get provider status: %w", err)
+	}
+	providerStatus := &v1.NvidiaCarbideMachineProviderStatus{
+		InstanceID:     instance.Id,
+		Conditions:     previousStatus.Conditions,
+		BootSourceHash: bootSourceHash(providerSpec),
 	}
+	meta.SetStatusCondition(&providerStatus.Conditions, credentialsAcceptedCondition())
+	meta.SetStatusCondition(&providerStatus.Conditions, capacityAvailableCondition())
+	meta.SetStatusCondition(&providerStatus.Conditions, referencesResolvedCondition())
+	meta.SetStatusCondition(&providerStatus.Conditions, providerSpecFieldsCondition(fieldProblems))
 
 	if instance.MachineId.Get() != nil {
 		providerStatus.MachineID = instance.MachineId.Get()
@@ -214,7 +296,7 @@ func (a *Actuator) Create(ctx context.Context, machine runtime.Object) error {
 	// Extract addresses
 	for _, iface := range instance.Interfaces {
 		for _, ipAddr := range iface.IpAddresses {
-			providerStatus.Addresses = append(providerStatus.Addresses, v1beta1.MachineAddress{
+			providerStatus.Addresses = append(providerStatus.Addresses, v1.MachineAddress{
 				Type:    "InternalIP",
 				Address: ipAddr,
 			})
@@ -230,7 +312,10 @@ func (a *Actuator) Create(ctx context.Context, machine runtime.Object) error {
 	if err != nil {
 		return fmt.Errorf("failed to parse instance ID from response: %w", err)
 	}
-	pid := providerid.NewProviderID(orgName, providerSpec.TenantID, providerSpec.SiteID, instanceUUID)
+	pid, err := providerid.NewProviderID(orgName, providerSpec.TenantID, providerSpec.SiteID, instanceUUID)
+	if err != nil {
+		return fmt.Errorf("failed to build provider ID: %w", err)
+	}
 	if err := a.setProviderID(machineObj, pid.String()); err != nil {
 		return fmt.Errorf("failed to set provider ID: %w", err)
 	}
@@ -242,7 +327,9 @@ func (a *Actuator) Create(ctx context.Context, machine runtime.Object) error {
 }
 
 // Update updates an existing instance
-func (a *Actuator) Update(ctx context.Context, machine runtime.Object) error {
+func (a *Actuator) Update(ctx context.Context, machine runtime.Object) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "Actuator.Update", machineAttributes(machine)...)
+	defer func() { tracing.EndSpan(span, err) }()
 	machineObj, ok := machine.(client.Object)
 	if !ok {
 		return fmt.Errorf("machine is not a client.Object")
@@ -265,7 +352,7 @@ func (a *Actuator) Update(ctx context.Context, machine runtime.Object) error {
 	}
 
 	// Get NVIDIA Carbide client and orgName
-	nvidiaCarbideClient, orgName, err := a.getNvidiaCarbideClient(ctx, providerSpec)
+	nvidiaCarbideClient, orgName, err := a.getNvidiaCarbideClient(ctx, machineObj, providerSpec)
 	if err != nil {
 		return fmt.Errorf("failed to create NVIDIA Carbide client: %w", err)
 	}
@@ -273,6 +360,7 @@ func (a *Actuator) Update(ctx context.Context, machine runtime.Object) error {
 	// Get current instance status
 	instance, httpResp, err := nvidiaCarbideClient.GetInstance(ctx, orgName, *providerStatus.InstanceID)
 	if err != nil {
+		a.reportCredentialsRejected(ctx, machineObj, err)
 		return fmt.Errorf("failed to get instance: %w", err)
 	}
 
@@ -280,20 +368,53 @@ func (a *Actuator) Update(ctx context.Context, machine runtime.Object) error {
 		return fmt.Errorf("get instance returned no data, status code: %d", httpResp.StatusCode)
 	}
 
+	a.syncDeletionProtectionLabel(ctx, nvidiaCarbideClient, orgName, machineObj, providerSpec, instance)
+
 	// Update provider status
+	meta.SetStatusCondition(&providerStatus.Conditions, credentialsAcceptedCondition())
+
+	fieldProblems, err := providerSpecFieldProblems(machineObj)
+	if err != nil {
+		return fmt.Errorf("failed to get provider spec: %w", err)
+	}
+	if meta.SetStatusCondition(&providerStatus.Conditions, providerSpecFieldsCondition(fieldProblems)) &&
+		len(fieldProblems) > 0 && a.eventRecorder != nil {
+		a.eventRecorder.Event(machineObj, corev1.EventTypeWarning, v1.UnknownFieldsReason,
+			unknownFieldsMessage(fieldProblems))
+	}
+
+	// Changes to immutable fields have no effect on the instance, tell the user
+	drift := instanceFieldDrift(providerSpec, instance)
+	if meta.SetStatusCondition(&providerStatus.Conditions, providerSpecAppliedCondition(drift)) &&
+		len(drift) > 0 && a.eventRecorder != nil {
+		a.eventRecorder.Event(machineObj, corev1.EventTypeWarning, v1.ImmutableFieldChangedReason,
+			ImmutableFieldsMessage(drift, ImmutableFields))
+	}
 	if instance.Status != nil {
 		status := string(*instance.Status)
+		if updateProvisionedTime(providerStatus, *instance.Status) {
+			provisioningTime := providerStatus.ProvisionedTime.Sub(machineObj.GetCreationTimestamp().Time)
+			metrics.MachineProvisioningDuration.Observe(provisioningTime.Seconds())
+		}
 		providerStatus.InstanceState = &status
 	}
 	if instance.MachineId.Get() != nil {
 		providerStatus.MachineID = instance.MachineId.Get()
 	}
+	if updateReprovisionProgress(providerStatus, instance) && a.eventRecorder != nil {
+		condition := meta.FindStatusCondition(providerStatus.Conditions, v1.ReprovisionedCondition)
+		eventType := corev1.EventTypeNormal
+		if condition.Status != metav1.ConditionTrue {
+			eventType = corev1.EventTypeWarning
+		}
+		a.eventRecorder.Event(machineObj, eventType, condition.Reason, condition.Message)
+	}
 
 	// Update addresses
-	providerStatus.Addresses = []v1beta1.MachineAddress{}
+	providerStatus.Addresses = []v1.MachineAddress{}
 	for _, iface := range instance.Interfaces {
 		for _, ipAddr := range iface.IpAddresses {
-			providerStatus.Addresses = append(providerStatus.Addresses, v1beta1.MachineAddress{
+			providerStatus.Addresses = append(providerStatus.Addresses, v1.MachineAddress{
 				Type:    "InternalIP",
 				Address: ipAddr,
 			})
@@ -307,8 +428,23 @@ func (a *Actuator) Update(ctx context.Context, machine runtime.Object) error {
 	return nil
 }
 
+// updateProvisionedTime records when the instance of a Machine is first seen
+// Ready, before InstanceState is updated, and reports whether it just became
+// Ready so that reboots and re-images are not counted as provisioning.
+// Instances already Ready before the time was recorded are not reported.
+func updateProvisionedTime(status *v1.NvidiaCarbideMachineProviderStatus, instanceStatus bmm.InstanceStatus) bool {
+	if instanceStatus != bmm.INSTANCESTATUS_READY || status.ProvisionedTime != nil {
+		return false
+	}
+	now := metav1.Now()
+	status.ProvisionedTime = &now
+	return status.InstanceState == nil || *status.InstanceState != string(instanceStatus)
+}
+
 // Exists checks if instance exists
-func (a *Actuator) Exists(ctx context.Context, machine runtime.Object) (bool, error) {
+func (a *Actuator) Exists(ctx context.Context, machine runtime.Object) (exists bool, err error) {
+	ctx, span := tracing.StartSpan(ctx, "Actuator.Exists", machineAttributes(machine)...)
+	defer func() { tracing.EndSpan(span, err) }()
 	machineObj, ok := machine.(client.Object)
 	if !ok {
 		return false, fmt.Errorf("machine is not a client.Object")
@@ -331,7 +467,7 @@ func (a *Actuator) Exists(ctx context.Context, machine runtime.Object) (bool, er
 	}
 
 	// Get NVIDIA Carbide client and orgName
-	nvidiaCarbideClient, orgName, err := a.getNvidiaCarbideClient(ctx, providerSpec)
+	nvidiaCarbideClient, orgName, err := a.getNvidiaCarbideClient(ctx, machineObj, providerSpec)
 	if err != nil {
 		return false, fmt.Errorf("failed to create NVIDIA Carbide client: %w", err)
 	}
@@ -339,15 +475,26 @@ func (a *Actuator) Exists(ctx context.Context, machine runtime.Object) (bool, er
 	// Check if instance exists
 	instance, _, err := nvidiaCarbideClient.GetInstance(ctx, orgName, *providerStatus.InstanceID)
 	if err != nil {
+		// A rejected token says nothing about the instance, so do not report
+		// it as missing and trigger a create
+		if errors.As(err, new(*CredentialsRejectedError)) {
+			a.reportCredentialsRejected(ctx, machineObj, err)
+			return false, fmt.Errorf("failed to get instance: %w", err)
+		}
 		return false, nil
 	}
+	if err := a.setCondition(machineObj, credentialsAcceptedCondition()); err != nil {
+		return false, fmt.Errorf("failed to set credentials condition: %w", err)
+	}
 
 	// Instance exists if we get a non-nil instance
 	return instance != nil, nil
 }
 
 // Delete deprovisions the instance
-func (a *Actuator) Delete(ctx context.Context, machine runtime.Object) error {
+func (a *Actuator) Delete(ctx context.Context, machine runtime.Object) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "Actuator.Delete", machineAttributes(machine)...)
+	defer func() { tracing.EndSpan(span, err) }()
 	machineObj, ok := machine.(client.Object)
 	if !ok {
 		return fmt.Errorf("machine is not a client.Object")
@@ -371,7 +518,7 @@ func (a *Actuator) Delete(ctx context.Context, machine runtime.Object) error {
 	}
 
 	// Get NVIDIA Carbide client and orgName
-	nvidiaCarbideClient, orgName, err := a.getNvidiaCarbideClient(ctx, providerSpec)
+	nvidiaCarbideClient, orgName, err := a.getNvidiaCarbideClient(ctx, machineObj, providerSpec)
 	if err != nil {
 		return fmt.Errorf("failed to create NVIDIA Carbide client: %w", err)
 	}
@@ -386,6 +533,8 @@ func (a *Actuator) Delete(ctx context.Context, machine runtime.Object) error {
 					"Instance %s already deleted (404)", *providerStatus.InstanceID)
 			}
 		} else {
+			metrics.RecordDelete(err)
+			a.reportCredentialsRejected(ctx, machineObj, err)
 			if a.eventRecorder != nil {
 				a.eventRecorder.Eventf(machineObj, corev1.EventTypeWarning, "FailedDelete", "Failed to delete instance: %v", err)
 			}
@@ -394,12 +543,15 @@ func (a *Actuator) Delete(ctx context.Context, machine runtime.Object) error {
 	} else if httpResp.StatusCode != 200 && httpResp.StatusCode != 202 &&
 		httpResp.StatusCode != 204 && httpResp.StatusCode != 404 {
 		// Accept 200 (OK), 202 (Accepted/async), 204 (No Content), 404 (already gone)
+		err := fmt.Errorf("delete instance returned unexpected status: %d", httpResp.StatusCode)
+		metrics.RecordDelete(err)
 		if a.eventRecorder != nil {
 			a.eventRecorder.Eventf(machineObj, corev1.EventTypeWarning, "FailedDelete",
 				"Delete instance returned unexpected status: %d", httpResp.StatusCode)
 		}
-		return fmt.Errorf("delete instance returned unexpected status: %d", httpResp.StatusCode)
+		return err
 	}
+	metrics.RecordDelete(nil)
 
 	if a.eventRecorder != nil {
 		a.eventRecorder.Eventf(machineObj, corev1.EventTypeNormal, "Deleted",
@@ -410,43 +562,111 @@ func (a *Actuator) Delete(ctx context.Context, machine runtime.Object) error {
 
 // Helper functions
 
-func (a *Actuator) getProviderSpec(machine client.Object) (*v1beta1.NvidiaCarbideMachineProviderSpec, error) {
-	var providerSpecBytes []byte
+func (a *Actuator) getProviderSpec(machine client.Object) (*v1.NvidiaCarbideMachineProviderSpec, error) {
+	providerSpecBytes, err := getProviderSpecBytes(machine)
+	if err != nil {
+		return nil, err
+	}
+	return decodeProviderSpec(providerSpecBytes)
+}
 
+// getProviderSpecBytes returns the raw provider spec of a typed or
+// unstructured Machine
+func getProviderSpecBytes(machine client.Object) ([]byte, error) {
 	switch m := machine.(type) {
 	case *machinev1beta1.Machine:
 		if m.Spec.ProviderSpec.Value == nil {
 			return nil, fmt.Errorf("providerSpec.value is nil")
 		}
-		providerSpecBytes = m.Spec.ProviderSpec.Value.Raw
+		return m.Spec.ProviderSpec.Value.Raw, nil
 	case *unstructured.Unstructured:
 		providerSpecValue, found, err := unstructured.NestedFieldCopy(m.Object, "spec", "providerSpec", "value")
 		if err != nil || !found {
 			return nil, fmt.Errorf("providerSpec.value not found: %w", err)
 		}
-		providerSpecBytes, err = json.Marshal(providerSpecValue)
+		providerSpecBytes, err := json.Marshal(providerSpecValue)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal providerSpec: %w", err)
 		}
+		return providerSpecBytes, nil
 	default:
 		return nil, fmt.Errorf("unsupported machine type: %T", machine)
 	}
+}
+
+// ProviderSpecKind is the kind of the provider spec handled by this provider
+const ProviderSpecKind = "NvidiaCarbideMachineProviderSpec"
+
+// IsOwnProviderSpec reports whether an embedded provider spec belongs to this
+// provider, from its kind and API group only. Provider specs without kind or
+// apiVersion are taken as ours, other kinds and groups belong to another
+// provider and must not be decoded.
+func IsOwnProviderSpec(raw *runtime.RawExtension) (bool, error) {
+	if raw == nil || len(raw.Raw) == 0 {
+		return false, nil
+	}
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw.Raw, &typeMeta); err != nil {
+		return false, fmt.Errorf("failed to unmarshal providerSpec: %w", err)
+	}
+	if typeMeta.Kind != "" && typeMeta.Kind != ProviderSpecKind {
+		return false, nil
+	}
+	if typeMeta.APIVersion != "" {
+		gv, err := schema.ParseGroupVersion(typeMeta.APIVersion)
+		if err != nil || gv.Group != v1.SchemeGroupVersion.Group {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ProviderSpecFromRawExtension decodes a provider spec embedded in a Machine
+// or MachineSet template
+func ProviderSpecFromRawExtension(raw *runtime.RawExtension) (*v1.NvidiaCarbideMachineProviderSpec, error) {
+	if raw == nil {
+		return nil, fmt.Errorf("providerSpec.value is nil")
+	}
+	return decodeProviderSpec(raw.Raw)
+}
 
-	providerSpec := &v1beta1.NvidiaCarbideMachineProviderSpec{}
-	if err := json.Unmarshal(providerSpecBytes, providerSpec); err != nil {
+// decodeProviderSpec decodes a provider spec of any supported version into
+// the v1 provider spec, picking the version from the embedded apiVersion.
+// Provider specs without apiVersion are v1beta1. Field names are matched
+// case-sensitively, as in providerSpecFieldErrors, so that a field reported as
+// unknown is never applied.
+func decodeProviderSpec(providerSpecBytes []byte) (*v1.NvidiaCarbideMachineProviderSpec, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := kjson.UnmarshalCaseSensitivePreserveInts(providerSpecBytes, &typeMeta); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal providerSpec: %w", err)
 	}
 
+	providerSpec := &v1.NvidiaCarbideMachineProviderSpec{}
+	switch typeMeta.APIVersion {
+	case v1.SchemeGroupVersion.String():
+		if err := kjson.UnmarshalCaseSensitivePreserveInts(providerSpecBytes, providerSpec); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal providerSpec: %w", err)
+		}
+	case "", v1beta1.SchemeGroupVersion.String():
+		v1beta1Spec := &v1beta1.NvidiaCarbideMachineProviderSpec{}
+		if err := kjson.UnmarshalCaseSensitivePreserveInts(providerSpecBytes, v1beta1Spec); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal providerSpec: %w", err)
+		}
+		v1beta1Spec.ConvertTo(providerSpec)
+	default:
+		return nil, fmt.Errorf("unsupported providerSpec apiVersion %q", typeMeta.APIVersion)
+	}
+
 	return providerSpec, nil
 }
 
-func (a *Actuator) getProviderStatus(machine client.Object) (*v1beta1.NvidiaCarbideMachineProviderStatus, error) {
+func (a *Actuator) getProviderStatus(machine client.Object) (*v1.NvidiaCarbideMachineProviderStatus, error) {
 	var providerStatusBytes []byte
 
 	switch m := machine.(type) {
 	case *machinev1beta1.Machine:
 		if m.Status.ProviderStatus == nil {
-			return &v1beta1.NvidiaCarbideMachineProviderStatus{}, nil
+			return &v1.NvidiaCarbideMachineProviderStatus{}, nil
 		}
 		providerStatusBytes = m.Status.ProviderStatus.Raw
 	case *unstructured.Unstructured:
@@ -455,7 +675,7 @@ func (a *Actuator) getProviderStatus(machine client.Object) (*v1beta1.NvidiaCarb
 			return nil, fmt.Errorf("failed to get providerStatus: %w", err)
 		}
 		if !found {
-			return &v1beta1.NvidiaCarbideMachineProviderStatus{}, nil
+			return &v1.NvidiaCarbideMachineProviderStatus{}, nil
 		}
 		providerStatusBytes, err = json.Marshal(providerStatusValue)
 		if err != nil {
@@ -465,7 +685,7 @@ func (a *Actuator) getProviderStatus(machine client.Object) (*v1beta1.NvidiaCarb
 		return nil, fmt.Errorf("unsupported machine type: %T", machine)
 	}
 
-	providerStatus := &v1beta1.NvidiaCarbideMachineProviderStatus{}
+	providerStatus := &v1.NvidiaCarbideMachineProviderStatus{}
 	if err := json.Unmarshal(providerStatusBytes, providerStatus); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal providerStatus: %w", err)
 	}
@@ -473,7 +693,7 @@ func (a *Actuator) getProviderStatus(machine client.Object) (*v1beta1.NvidiaCarb
 	return providerStatus, nil
 }
 
-func (a *Actuator) setProviderStatus(machine client.Object, status *v1beta1.NvidiaCarbideMachineProviderStatus) error {
+func (a *Actuator) setProviderStatus(machine client.Object, status *v1.NvidiaCarbideMachineProviderStatus) error {
 	statusBytes, err := json.Marshal(status)
 	if err != nil {
 		return fmt.Errorf("failed to marshal status: %w", err)
@@ -525,48 +745,58 @@ func (a *Actuator) setProviderID(machine client.Object, providerID string) error
 }
 
 func (a *Actuator) getNvidiaCarbideClient(
-	ctx context.Context, providerSpec *v1beta1.NvidiaCarbideMachineProviderSpec,
+	ctx context.Context, machineObj client.Object, providerSpec *v1.NvidiaCarbideMachineProviderSpec,
+) (NvidiaCarbideClientInterface, string, error) {
+	nvidiaCarbideClient, orgName, err := a.newNvidiaCarbideClient(ctx, machineObj.GetNamespace(), providerSpec)
+	if err != nil {
+		a.rejectCredentials(ctx, machineObj, err)
+		return nil, "", err
+	}
+	return nvidiaCarbideClient, orgName, nil
+}
+
+// newNvidiaCarbideClient returns a client using the credentials Secret of a
+// provider spec from namespace, without reporting failures on any object
+func (a *Actuator) newNvidiaCarbideClient(
+	ctx context.Context, namespace string, providerSpec *v1.NvidiaCarbideMachineProviderSpec,
 ) (NvidiaCarbideClientInterface, string, error) {
+	// Resolve and authorize the credentials Secret reference
+	secretKey, err := a.credentialsPolicy.resolve(namespace, providerSpec.CredentialsSecret)
+	if err != nil {
+		return nil, "", err
+	}
+
 	// Use injected client for testing
 	if a.nvidiaCarbideClient != nil {
 		return a.nvidiaCarbideClient, a.orgName, nil
 	}
 
-	// Fetch credentials secret
-	secret := &corev1.Secret{}
-	secretKey := client.ObjectKey{
-		Name:      providerSpec.CredentialsSecret.Name,
-		Namespace: providerSpec.CredentialsSecret.Namespace,
+	creds, err := readCredentials(ctx, a.client, secretKey)
+	if err != nil {
+		return nil, "", err
 	}
 
-	if err := a.client.Get(ctx, secretKey, secret); err != nil {
-		return nil, "", fmt.Errorf("failed to get credentials secret: %w", err)
-	}
+	return newCarbideClient(creds, secretKey, a.apiReader()), creds.orgName, nil
+}
 
-	// Validate secret contains required fields
-	endpoint, ok := secret.Data["endpoint"]
-	if !ok {
-		return nil, "", fmt.Errorf("secret %s is missing 'endpoint' field", secretKey.Name)
+// apiReader returns the reader used to fetch credentials Secrets
+func (a *Actuator) apiReader() client.Reader {
+	if a.reader != nil {
+		return a.reader
 	}
-	orgName, ok := secret.Data["orgName"]
-	if !ok {
-		return nil, "", fmt.Errorf("secret %s is missing 'orgName' field", secretKey.Name)
-	}
-	token, ok := secret.Data["token"]
+	return a.client
+}
+
+// machineAttributes returns the span attributes identifying a Machine
+func machineAttributes(machine runtime.Object) []attribute.KeyValue {
+	machineObj, ok := machine.(client.Object)
 	if !ok {
-		return nil, "", fmt.Errorf("secret %s is missing 'token' field", secretKey.Name)
+		return nil
 	}
-
-	// Create NVIDIA Carbide API client
-	sdkCfg := bmm.NewConfiguration()
-	sdkCfg.Servers = bmm.ServerConfigurations{
-		{URL: string(endpoint)},
+	return []attribute.KeyValue{
+		attribute.String("machine.namespace", machineObj.GetNamespace()),
+		attribute.String("machine.name", machineObj.GetName()),
 	}
-
-	return &carbideClient{
-		client: bmm.NewAPIClient(sdkCfg),
-		token:  string(token),
-	}, string(orgName), nil
 }
 
 // ptr is a helper function to get a pointer to a value
@@ -17,19 +17,35 @@ limitations under the License.
 package machine
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
 	v1beta1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1beta1"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/metrics"
 	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/providerid"
+	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
 )
 
 func TestActuator_Create(t *testing.T) {
@@ -99,6 +115,48 @@ func TestActuator_Create(t *testing.T) {
 	}
 }
 
+func TestActuator_CreateRecordsEarlyFailures(t *testing.T) {
+	invalidSpec := createTestMachine(v1beta1.NvidiaCarbideMachineProviderSpec{})
+	_ = unstructured.SetNestedField(invalidSpec.Object, int64(42), "spec", "providerSpec", "value", "siteId")
+
+	tests := []struct {
+		name    string
+		machine *unstructured.Unstructured
+	}{
+		{
+			name:    "invalid provider spec",
+			machine: invalidSpec,
+		},
+		{
+			name: "missing credentials secret",
+			machine: createTestMachine(v1beta1.NvidiaCarbideMachineProviderSpec{
+				SiteID: "site",
+				CredentialsSecret: v1beta1.CredentialsSecretReference{
+					Name:      "missing-creds",
+					Namespace: "default",
+				},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			a := NewActuator(fake.NewClientBuilder().WithScheme(scheme).Build(), record.NewFakeRecorder(10))
+
+			failures := metrics.MachineCreateTotal.WithLabelValues(metrics.ResultFailure)
+			before := testutil.ToFloat64(failures)
+			if err := a.Create(context.Background(), tt.machine); err == nil {
+				t.Fatal("expected Create to fail")
+			}
+			if got := testutil.ToFloat64(failures) - before; got != 1 {
+				t.Errorf("expected 1 recorded create failure, got %v", got)
+			}
+		})
+	}
+}
+
 func createTestMachine(providerSpec v1beta1.NvidiaCarbideMachineProviderSpec) *unstructured.Unstructured {
 	machine := &unstructured.Unstructured{}
 	machine.SetGroupVersionKind(schema.GroupVersionKind{
@@ -117,7 +175,10 @@ func createTestMachine(providerSpec v1beta1.NvidiaCarbideMachineProviderSpec) *u
 }
 
 func TestProviderIDParsing(t *testing.T) {
-	pid := providerid.NewProviderID("test-org", "test-tenant", "test-site", uuid.New())
+	pid, err := providerid.NewProviderID("test-org", "test-tenant", "test-site", uuid.New())
+	if err != nil {
+		t.Fatalf("Failed to build provider ID: %v", err)
+	}
 
 	parsed, err := providerid.ParseProviderID(pid.String())
 	if err != nil {
@@ -134,3 +195,1275 @@ func TestProviderIDParsing(t *testing.T) {
 		t.Errorf("Expected siteName=test-site, got %s", parsed.SiteName)
 	}
 }
+
+func TestCredentialsPolicyResolve(t *testing.T) {
+	defaultSecret := client.ObjectKey{Namespace: "openshift-machine-api", Name: "default-creds"}
+
+	tests := []struct {
+		name       string
+		policy     CredentialsPolicy
+		ref        *corev1.SecretReference
+		want       client.ObjectKey
+		wantReason string
+	}{
+		{
+			name:   "explicit reference without restrictions",
+			policy: CredentialsPolicy{},
+			ref:    &corev1.SecretReference{Name: "creds", Namespace: "other"},
+			want:   client.ObjectKey{Namespace: "other", Name: "creds"},
+		},
+		{
+			name:   "namespace defaults to the machine namespace",
+			policy: CredentialsPolicy{},
+			ref:    &corev1.SecretReference{Name: "creds"},
+			want:   client.ObjectKey{Namespace: "machines", Name: "creds"},
+		},
+		{
+			name:   "empty reference uses the default secret",
+			policy: CredentialsPolicy{DefaultSecret: &defaultSecret},
+			want:   defaultSecret,
+		},
+		{
+			name:       "empty reference without default",
+			policy:     CredentialsPolicy{},
+			wantReason: v1.CredentialsSecretNotSpecifiedReason,
+		},
+		{
+			name:   "allowed namespace",
+			policy: CredentialsPolicy{AllowedNamespaces: []string{"openshift-machine-api"}},
+			ref:    &corev1.SecretReference{Name: "creds", Namespace: "openshift-machine-api"},
+			want:   client.ObjectKey{Namespace: "openshift-machine-api", Name: "creds"},
+		},
+		{
+			name:       "namespace outside the allow-list",
+			policy:     CredentialsPolicy{AllowedNamespaces: []string{"openshift-machine-api"}},
+			ref:        &corev1.SecretReference{Name: "creds", Namespace: "kube-system"},
+			wantReason: v1.CredentialsSecretNotAllowedReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.resolve("machines", tt.ref)
+			if tt.wantReason != "" {
+				policyErr := &CredentialsPolicyError{}
+				if !errors.As(err, &policyErr) {
+					t.Fatalf("expected CredentialsPolicyError, got %v", err)
+				}
+				if policyErr.Reason != tt.wantReason {
+					t.Errorf("expected reason %s, got %s", tt.wantReason, policyErr.Reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCarbideClient_RetriesWithRotatedToken(t *testing.T) {
+	instanceID := uuid.New().String()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer rotated-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"` + instanceID + `"}`))
+	}))
+	defer server.Close()
+
+	// movedServer is the endpoint a rotated Secret may point to
+	movedRequests := 0
+	movedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		movedRequests++
+		if r.Header.Get("Authorization") != "Bearer rotated-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"` + instanceID + `"}`))
+	}))
+	defer movedServer.Close()
+
+	secretKey := client.ObjectKey{Namespace: "default", Name: "nvidia-carbide-creds"}
+
+	tests := []struct {
+		name           string
+		secretToken    string
+		secretEndpoint string
+		secretOrg      string
+		noSecret       bool
+		wantErr        bool
+		wantRejected   bool
+		wantMoved      bool
+	}{
+		{
+			name:        "token rotated in secret",
+			secretToken: "rotated-token",
+			wantErr:     false,
+		},
+		{
+			name:         "token still invalid",
+			secretToken:  "stale-token",
+			wantErr:      true,
+			wantRejected: true,
+		},
+		{
+			name:     "secret cannot be re-read",
+			noSecret: true,
+			wantErr:  true,
+		},
+		{
+			name:           "endpoint rotated in secret",
+			secretToken:    "rotated-token",
+			secretEndpoint: movedServer.URL,
+			wantMoved:      true,
+		},
+		{
+			name:        "organization changed in secret",
+			secretToken: "rotated-token",
+			secretOrg:   "other-org",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			movedRequests = 0
+			endpoint, orgName := server.URL, "test-org"
+			if tt.secretEndpoint != "" {
+				endpoint = tt.secretEndpoint
+			}
+			if tt.secretOrg != "" {
+				orgName = tt.secretOrg
+			}
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace},
+				Data: map[string][]byte{
+					"endpoint": []byte(endpoint),
+					"orgName":  []byte(orgName),
+					"token":    []byte(tt.secretToken),
+				},
+			}
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if !tt.noSecret {
+				builder = builder.WithObjects(secret)
+			}
+			fakeClient := builder.Build()
+
+			c := newCarbideClient(&credentials{
+				endpoint: server.URL,
+				orgName:  "test-org",
+				token:    "stale-token",
+			}, secretKey, fakeClient)
+
+			instance, _, err := c.GetInstance(context.Background(), "test-org", instanceID)
+			if tt.wantErr && !tt.wantRejected {
+				if err == nil || errors.As(err, new(*CredentialsRejectedError)) {
+					t.Fatalf("expected a secret read error, got %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				rejectedErr := &CredentialsRejectedError{}
+				if !errors.As(err, &rejectedErr) {
+					t.Fatalf("expected CredentialsRejectedError, got %v", err)
+				}
+				if rejectedErr.SecretKey != secretKey {
+					t.Errorf("expected secret %v, got %v", secretKey, rejectedErr.SecretKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if instance == nil || instance.Id == nil || *instance.Id != instanceID {
+				t.Errorf("expected instance %s, got %+v", instanceID, instance)
+			}
+			if tt.wantMoved && movedRequests != 1 {
+				t.Errorf("expected the retry to use the rotated endpoint, got %d requests", movedRequests)
+			}
+		})
+	}
+}
+
+// Run with -race: concurrent requests share the client token across a rotation
+func TestCarbideClient_ConcurrentTokenRotation(t *testing.T) {
+	instanceID := uuid.New().String()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer rotated-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"` + instanceID + `"}`))
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	secretKey := client.ObjectKey{Namespace: "default", Name: "nvidia-carbide-creds"}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace},
+		Data: map[string][]byte{
+			"endpoint": []byte(server.URL),
+			"orgName":  []byte("test-org"),
+			"token":    []byte("rotated-token"),
+		},
+	}).Build()
+
+	c := newCarbideClient(&credentials{
+		endpoint: server.URL,
+		orgName:  "test-org",
+		token:    "stale-token",
+	}, secretKey, fakeClient)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := c.GetInstance(context.Background(), "test-org", instanceID); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, creds := c.current(); creds.token != "rotated-token" {
+		t.Errorf("expected the rotated token, got %q", creds.token)
+	}
+}
+
+func TestCapacityFromInstanceType(t *testing.T) {
+	instanceType := &bmm.InstanceType{}
+	err := json.Unmarshal([]byte(`{
+		"machineCapabilities": [
+			{"type": "CPU", "name": "Xeon", "cores": 32, "threads": 64, "count": 2},
+			{"type": "Memory", "name": "DDR5", "capacity": "64GB", "count": 16},
+			{"type": "GPU", "name": "H100", "count": 8},
+			{"type": "InfiniBand", "name": "ConnectX-7", "count": 8}
+		]
+	}`), instanceType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	capacity, err := capacityFromInstanceType(instanceType)
+	if err != nil {
+		t.Fatalf("capacityFromInstanceType() error = %v", err)
+	}
+
+	want := InstanceTypeCapacity{VCPU: 128, MemoryMiB: 1024 * 1024, GPU: 8}
+	if *capacity != want {
+		t.Errorf("expected %+v, got %+v", want, *capacity)
+	}
+}
+
+func TestParseMemoryMiB(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "64GB", want: 64 * 1024},
+		{value: "64 GiB", want: 64 * 1024},
+		{value: "512MB", want: 512},
+		{value: "1TB", want: 1024 * 1024},
+		{value: "lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseMemoryMiB(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMemoryMiB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHasAvailableCapacity(t *testing.T) {
+	zero, two := int32(0), int32(2)
+
+	tests := []struct {
+		name         string
+		instanceType *bmm.InstanceType
+		want         bool
+	}{
+		{
+			name:         "no allocation stats",
+			instanceType: &bmm.InstanceType{},
+			want:         true,
+		},
+		{
+			name: "usable hosts",
+			instanceType: &bmm.InstanceType{
+				AllocationStats: &bmm.InstanceTypeAllocationStats{UnusedUsable: &two},
+			},
+			want: true,
+		},
+		{
+			name: "no usable host",
+			instanceType: &bmm.InstanceType{
+				AllocationStats: &bmm.InstanceTypeAllocationStats{UnusedUsable: &zero},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasAvailableCapacity(tt.instanceType); got != tt.want {
+				t.Errorf("hasAvailableCapacity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCapacity(t *testing.T) {
+	zero, two := int32(0), int32(2)
+	full := &bmm.InstanceType{AllocationStats: &bmm.InstanceTypeAllocationStats{UnusedUsable: &zero}}
+
+	tests := []struct {
+		name         string
+		machineID    string
+		instanceType *bmm.InstanceType
+		wantErr      bool
+	}{
+		{
+			name:         "no usable host",
+			instanceType: full,
+			wantErr:      true,
+		},
+		{
+			name:         "specific machine requested",
+			machineID:    "machine-1",
+			instanceType: full,
+		},
+		{
+			name: "instance type lookup failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakeReferenceClient{instanceTypes: map[string]*bmm.InstanceType{}}
+			if tt.instanceType != nil {
+				fakeClient.instanceTypes["instance-type"] = tt.instanceType
+			}
+			spec := &v1.NvidiaCarbideMachineProviderSpec{
+				SiteID:         "site",
+				InstanceTypeID: "instance-type",
+				MachineID:      tt.machineID,
+			}
+			err := (&Actuator{}).checkCapacity(context.Background(), fakeClient, "org", spec)
+			if IsInsufficientCapacity(err) != tt.wantErr {
+				t.Errorf("checkCapacity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The allocation statistics are read again after the pre-flight checks
+	// cached the instance type
+	t.Run("cached instance type", func(t *testing.T) {
+		site := "site"
+		fakeClient := &fakeReferenceClient{
+			sites: map[string]*bmm.Site{site: {Id: &site}},
+			vpcs:  map[string]*bmm.VPC{"vpc": {SiteId: &site}},
+			instanceTypes: map[string]*bmm.InstanceType{"instance-type": {
+				SiteId:          &site,
+				AllocationStats: &bmm.InstanceTypeAllocationStats{UnusedUsable: &two},
+			}},
+		}
+		spec := &v1.NvidiaCarbideMachineProviderSpec{SiteID: site, VpcID: "vpc", InstanceTypeID: "instance-type"}
+		a := &Actuator{preflight: newPreflightCache(preflightCacheTTL)}
+		if err := a.checkReferences(context.Background(), fakeClient, "org", client.ObjectKey{}, spec); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		fakeClient.instanceTypes["instance-type"] = &bmm.InstanceType{SiteId: &site, AllocationStats: full.AllocationStats}
+		if err := a.checkReferences(context.Background(), fakeClient, "org", client.ObjectKey{}, spec); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := a.checkCapacity(context.Background(), fakeClient, "org", spec); !IsInsufficientCapacity(err) {
+			t.Errorf("expected InsufficientCapacityError from fresh statistics, got %v", err)
+		}
+	})
+}
+
+func TestInstanceFieldDrift(t *testing.T) {
+	spec := &v1.NvidiaCarbideMachineProviderSpec{
+		SiteID:         "site-a",
+		TenantID:       "tenant",
+		VpcID:          "vpc-b",
+		Interfaces:     []v1.NetworkInterface{{SubnetID: "subnet-a"}},
+		InstanceTypeID: "type-b",
+	}
+	site, tenant, vpc, instanceType, subnet := "site-a", "tenant", "vpc-a", "type-a", "subnet-a"
+	instance := &bmm.Instance{
+		SiteId:         &site,
+		TenantId:       &tenant,
+		VpcId:          &vpc,
+		InstanceTypeId: &instanceType,
+		Interfaces:     []bmm.Interface{{SubnetId: *bmm.NewNullableString(&subnet)}},
+	}
+
+	drift := instanceFieldDrift(spec, instance)
+	if want := []string{"vpcId", "instanceTypeId"}; !slices.Equal(drift, want) {
+		t.Errorf("expected drift %v, got %v", want, drift)
+	}
+
+	spec.Interfaces = append(spec.Interfaces, v1.NetworkInterface{SubnetID: "subnet-b"})
+	spec.VpcID, spec.InstanceTypeID = vpc, instanceType
+	if drift := instanceFieldDrift(spec, instance); !slices.Equal(drift, []string{"interfaces"}) {
+		t.Errorf("expected interfaces drift, got %v", drift)
+	}
+}
+
+// fakeReferenceClient serves the resources it knows and 404 for the others
+type fakeReferenceClient struct {
+	NvidiaCarbideClientInterface
+	sites         map[string]*bmm.Site
+	vpcs          map[string]*bmm.VPC
+	subnets       map[string]*bmm.Subnet
+	instanceTypes map[string]*bmm.InstanceType
+	sshKeyGroups  map[string]*bmm.SshKeyGroup
+	calls         int
+}
+
+func fakeGet[T any](f *fakeReferenceClient, resources map[string]*T, id string) (*T, *http.Response, error) {
+	f.calls++
+	if resource, ok := resources[id]; ok {
+		return resource, &http.Response{StatusCode: http.StatusOK}, nil
+	}
+	return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("404 Not Found")
+}
+
+func (f *fakeReferenceClient) GetSite(_ context.Context, _, id string) (*bmm.Site, *http.Response, error) {
+	return fakeGet(f, f.sites, id)
+}
+
+func (f *fakeReferenceClient) GetVpc(_ context.Context, _, id string) (*bmm.VPC, *http.Response, error) {
+	return fakeGet(f, f.vpcs, id)
+}
+
+func (f *fakeReferenceClient) GetSubnet(_ context.Context, _, id string) (*bmm.Subnet, *http.Response, error) {
+	return fakeGet(f, f.subnets, id)
+}
+
+func (f *fakeReferenceClient) GetInstanceType(
+	_ context.Context, _, id string,
+) (*bmm.InstanceType, *http.Response, error) {
+	return fakeGet(f, f.instanceTypes, id)
+}
+
+func (f *fakeReferenceClient) GetSSHKeyGroup(
+	_ context.Context, _, id string,
+) (*bmm.SshKeyGroup, *http.Response, error) {
+	return fakeGet(f, f.sshKeyGroups, id)
+}
+
+func TestCheckReferences(t *testing.T) {
+	site, otherSite, tenant, otherTenant, vpc := "site", "other-site", "tenant", "other-tenant", "vpc"
+	newClient := func() *fakeReferenceClient {
+		return &fakeReferenceClient{
+			sites: map[string]*bmm.Site{site: {Id: &site}},
+			vpcs:  map[string]*bmm.VPC{vpc: {SiteId: &site, TenantId: &tenant}},
+			subnets: map[string]*bmm.Subnet{
+				"subnet":       {SiteId: &site, TenantId: &tenant, VpcId: &vpc},
+				"other-site":   {SiteId: &otherSite, TenantId: &tenant, VpcId: &vpc},
+				"other-tenant": {SiteId: &site, TenantId: &otherTenant, VpcId: &vpc},
+			},
+			instanceTypes: map[string]*bmm.InstanceType{
+				"type":       {SiteId: &site},
+				"other-type": {SiteId: &otherSite},
+			},
+			sshKeyGroups: map[string]*bmm.SshKeyGroup{
+				"keys":       {TenantId: &tenant},
+				"other-keys": {TenantId: &otherTenant},
+			},
+		}
+	}
+	validSpec := func() *v1.NvidiaCarbideMachineProviderSpec {
+		return &v1.NvidiaCarbideMachineProviderSpec{
+			SiteID:         site,
+			TenantID:       tenant,
+			VpcID:          vpc,
+			Interfaces:     []v1.NetworkInterface{{SubnetID: "subnet"}},
+			InstanceTypeID: "type",
+			SSHKeyGroupIDs: []string{"keys"},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(spec *v1.NvidiaCarbideMachineProviderSpec)
+		problems []string
+	}{
+		{
+			name:   "all references resolved",
+			mutate: func(spec *v1.NvidiaCarbideMachineProviderSpec) {},
+		},
+		{
+			name: "missing resources",
+			mutate: func(spec *v1.NvidiaCarbideMachineProviderSpec) {
+				spec.SiteID = "missing-site"
+				spec.VpcID = "missing-vpc"
+				spec.InstanceTypeID = "missing-type"
+				spec.SSHKeyGroupIDs = []string{"missing-keys"}
+			},
+			problems: []string{
+				"site missing-site not found",
+				"VPC missing-vpc not found",
+				"subnet subnet belongs to site site, not missing-site",
+				"instance type missing-type not found",
+				"SSH key group missing-keys not found",
+			},
+		},
+		{
+			name: "mismatched resources",
+			mutate: func(spec *v1.NvidiaCarbideMachineProviderSpec) {
+				spec.Interfaces = append(spec.Interfaces,
+					v1.NetworkInterface{SubnetID: "other-site"},
+					v1.NetworkInterface{SubnetID: "other-tenant"},
+					v1.NetworkInterface{SubnetID: "missing-subnet"},
+				)
+				spec.InstanceTypeID = "other-type"
+				spec.SSHKeyGroupIDs = []string{"other-keys"}
+			},
+			problems: []string{
+				"subnet other-site belongs to site other-site, not site",
+				"subnet other-tenant belongs to tenant other-tenant, not tenant",
+				"subnet missing-subnet not found",
+				"instance type other-type belongs to site other-site, not site",
+				"SSH key group other-keys belongs to tenant other-tenant, not tenant",
+			},
+		},
+		{
+			name: "specific machine skips instance type",
+			mutate: func(spec *v1.NvidiaCarbideMachineProviderSpec) {
+				spec.InstanceTypeID = ""
+				spec.MachineID = "machine"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := validSpec()
+			tt.mutate(spec)
+			a := &Actuator{preflight: newPreflightCache(preflightCacheTTL)}
+
+			err := a.checkReferences(context.Background(), newClient(), "org", client.ObjectKey{}, spec)
+			if tt.problems == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			invalidErr := &InvalidConfigurationError{}
+			if !errors.As(err, &invalidErr) {
+				t.Fatalf("expected InvalidConfigurationError, got %v", err)
+			}
+			if !slices.Equal(invalidErr.Problems, tt.problems) {
+				t.Errorf("expected problems %q, got %q", tt.problems, invalidErr.Problems)
+			}
+		})
+	}
+}
+
+func TestCheckReferences_Cache(t *testing.T) {
+	site, tenant, vpc := "site", "tenant", "vpc"
+	fakeClient := &fakeReferenceClient{
+		sites:   map[string]*bmm.Site{site: {Id: &site}},
+		vpcs:    map[string]*bmm.VPC{vpc: {SiteId: &site, TenantId: &tenant}},
+		subnets: map[string]*bmm.Subnet{},
+	}
+	spec := &v1.NvidiaCarbideMachineProviderSpec{
+		SiteID:     site,
+		TenantID:   tenant,
+		VpcID:      vpc,
+		Interfaces: []v1.NetworkInterface{{SubnetID: "missing-subnet"}},
+		MachineID:  "machine",
+	}
+	now := time.Now()
+	cache := newPreflightCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	a := &Actuator{preflight: cache}
+
+	for range 2 {
+		if err := a.checkReferences(context.Background(), fakeClient, "org", client.ObjectKey{}, spec); !IsInvalidConfiguration(err) {
+			t.Fatalf("expected InvalidConfigurationError, got %v", err)
+		}
+	}
+	if fakeClient.calls != 3 {
+		t.Errorf("expected found and missing resources to be cached, got %d calls", fakeClient.calls)
+	}
+
+	// Lookups made with other credentials are not reused
+	otherSecret := client.ObjectKey{Namespace: "other", Name: "other-credentials"}
+	if err := a.checkReferences(context.Background(), fakeClient, "org", otherSecret, spec); !IsInvalidConfiguration(err) {
+		t.Fatalf("expected InvalidConfigurationError, got %v", err)
+	}
+	if fakeClient.calls != 6 {
+		t.Errorf("expected lookups with another credentials secret not to be cached, got %d calls", fakeClient.calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	fakeClient.subnets["missing-subnet"] = &bmm.Subnet{SiteId: &site, TenantId: &tenant, VpcId: &vpc}
+	if err := a.checkReferences(context.Background(), fakeClient, "org", client.ObjectKey{}, spec); err != nil {
+		t.Fatalf("expected expired entries to be refreshed, got %v", err)
+	}
+}
+
+func TestProviderSpecFieldErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{
+			name: "known fields",
+			raw:  `{"siteId":"site","subnetId":"subnet","sshKeyGroupIds":["keys"]}`,
+		},
+		{
+			name: "misspelled fields",
+			raw:  `{"siteId":"site","subnetID":"subnet","sshKeyGroupIDs":["keys"]}`,
+			want: []string{`unknown field "subnetID"`, `unknown field "sshKeyGroupIDs"`},
+		},
+		{
+			name: "duplicate field",
+			raw:  `{"siteId":"site","siteId":"other"}`,
+			want: []string{`duplicate field "siteId"`},
+		},
+		{
+			name: "syntax error",
+			raw:  `{"siteId":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := providerSpecFieldErrors([]byte(tt.raw)); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeProviderSpec_CaseSensitive(t *testing.T) {
+	// Fields reported as unknown must not be applied
+	raw := `{"siteId":"site","subnetID":"subnet","sshKeyGroupIDs":["keys"]}`
+	if problems := providerSpecFieldErrors([]byte(raw)); len(problems) != 2 {
+		t.Fatalf("expected subnetID and sshKeyGroupIDs to be reported, got %q", problems)
+	}
+
+	spec, err := decodeProviderSpec([]byte(raw))
+	if err != nil {
+		t.Fatalf("decodeProviderSpec() error = %v", err)
+	}
+	if spec.SiteID != "site" {
+		t.Errorf("expected siteId to be applied, got %q", spec.SiteID)
+	}
+	if spec.PrimarySubnetID() != "" || len(spec.SSHKeyGroupIDs) != 0 {
+		t.Errorf("expected misspelled fields not to be applied, got subnet %q and SSH key groups %v",
+			spec.PrimarySubnetID(), spec.SSHKeyGroupIDs)
+	}
+}
+
+func TestCheckProviderSpecFields(t *testing.T) {
+	raw := `{"siteId":"site","subnetID":"subnet"}`
+	typed := &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "typed", Namespace: "default"},
+		Spec: machinev1beta1.MachineSpec{
+			ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{Raw: []byte(raw)}},
+		},
+	}
+	unstructuredMachine := createTestMachine(v1beta1.NvidiaCarbideMachineProviderSpec{SiteID: "site"})
+	if err := unstructured.SetNestedField(unstructuredMachine.Object, "subnet",
+		"spec", "providerSpec", "value", "subnetID"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, machineObj := range []client.Object{typed, unstructuredMachine} {
+		for _, strict := range []bool{false, true} {
+			recorder := record.NewFakeRecorder(10)
+			a := NewActuator(fake.NewClientBuilder().Build(), recorder, WithStrictProviderSpec(strict))
+
+			problems, err := a.checkProviderSpecFields(context.Background(), machineObj)
+			if !slices.Equal(problems, []string{`unknown field "subnetID"`}) {
+				t.Errorf("%T: expected subnetID to be reported, got %q", machineObj, problems)
+			}
+			if IsInvalidConfiguration(err) != strict {
+				t.Errorf("%T: strict=%t, got error %v", machineObj, strict, err)
+			}
+			if len(recorder.Events) != 1 {
+				t.Errorf("%T: expected a warning event, got %d", machineObj, len(recorder.Events))
+			}
+		}
+	}
+}
+
+func TestDecodeProviderSpec(t *testing.T) {
+	want := &v1.NvidiaCarbideMachineProviderSpec{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       "NvidiaCarbideMachineProviderSpec",
+		},
+		SiteID:            "site",
+		VpcID:             "vpc",
+		Interfaces:        []v1.NetworkInterface{{SubnetID: "subnet"}, {SubnetID: "storage", IsPhysical: true}},
+		BootSource:        v1.BootSource{UserData: "#cloud-config"},
+		CredentialsSecret: &corev1.SecretReference{Name: "creds"},
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    *v1.NvidiaCarbideMachineProviderSpec
+		wantErr bool
+	}{
+		{
+			name: "v1beta1",
+			raw: `{"apiVersion":"nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/v1beta1",` +
+				`"kind":"NvidiaCarbideMachineProviderSpec","siteId":"site","vpcId":"vpc","subnetId":"subnet",` +
+				`"additionalSubnetIds":[{"subnetId":"storage","isPhysical":true}],"userData":"#cloud-config",` +
+				`"credentialsSecret":{"name":"creds"}}`,
+			want: want,
+		},
+		{
+			name: "v1",
+			raw: `{"apiVersion":"nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/v1",` +
+				`"kind":"NvidiaCarbideMachineProviderSpec","siteId":"site","vpcId":"vpc",` +
+				`"interfaces":[{"subnetId":"subnet"},{"subnetId":"storage","isPhysical":true}],` +
+				`"bootSource":{"userData":"#cloud-config"},"credentialsSecret":{"name":"creds"}}`,
+			want: want,
+		},
+		{
+			name: "no apiVersion is v1beta1",
+			raw:  `{"siteId":"site","subnetId":"subnet"}`,
+			want: &v1.NvidiaCarbideMachineProviderSpec{
+				TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String()},
+				SiteID:     "site",
+				Interfaces: []v1.NetworkInterface{{SubnetID: "subnet"}},
+			},
+		},
+		{
+			name:    "unsupported apiVersion",
+			raw:     `{"apiVersion":"nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/v2","siteId":"site"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeProviderSpec([]byte(tt.raw))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(tt.want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("expected %s, got %s", wantJSON, gotJSON)
+			}
+		})
+	}
+}
+
+func TestBuildInstanceRequest_BootSource(t *testing.T) {
+	spec := &v1.NvidiaCarbideMachineProviderSpec{
+		Interfaces: []v1.NetworkInterface{{SubnetID: "subnet"}},
+		BootSource: v1.BootSource{OperatingSystemID: "os", UserData: "#cloud-config"},
+	}
+	req := buildInstanceRequest("machine", spec)
+	if got := req.OperatingSystemId.Get(); got == nil || *got != "os" {
+		t.Errorf("expected operating system os, got %v", got)
+	}
+	if req.IpxeScript.IsSet() {
+		t.Errorf("expected no iPXE script with an operating system, got %q", *req.IpxeScript.Get())
+	}
+	if got := req.UserData.Get(); got == nil || *got != "#cloud-config" {
+		t.Errorf("expected user data, got %v", got)
+	}
+
+	spec.BootSource = v1.BootSource{}
+	req = buildInstanceRequest("machine", spec)
+	if got := req.IpxeScript.Get(); got == nil || *got == "" {
+		t.Errorf("expected the default iPXE script, got %v", got)
+	}
+}
+
+// fakeRebootClient counts reboots and fails them with err
+type fakeRebootClient struct {
+	NvidiaCarbideClientInterface
+	reboots int
+	err     error
+}
+
+func (f *fakeRebootClient) RebootInstance(_ context.Context, _, _ string) (*http.Response, error) {
+	f.reboots++
+	if f.err != nil {
+		return &http.Response{StatusCode: http.StatusConflict}, f.err
+	}
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func TestActuator_Reboot(t *testing.T) {
+	newMachine := func(token string) *machinev1beta1.Machine {
+		return &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "worker-0",
+				Namespace:   "default",
+				Annotations: map[string]string{RebootRequestAnnotation: token},
+			},
+			Spec: machinev1beta1.MachineSpec{
+				ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{
+					Raw: []byte(`{"siteId":"site","credentialsSecret":{"name":"creds"}}`),
+				}},
+			},
+			Status: machinev1beta1.MachineStatus{
+				ProviderStatus: &runtime.RawExtension{Raw: []byte(`{"instanceId":"instance-1"}`)},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		tokens      []string
+		rebootErr   error
+		wantReboots int
+		wantResult  v1.RequestResult
+		wantEvents  int
+	}{
+		{
+			name:        "each token reboots once",
+			tokens:      []string{"1", "1", "2"},
+			wantReboots: 2,
+			wantResult:  v1.RequestSucceeded,
+			wantEvents:  2,
+		},
+		{
+			name:        "failed reboot is not retried",
+			tokens:      []string{"1", "1"},
+			rebootErr:   errors.New("409 Conflict"),
+			wantReboots: 1,
+			wantResult:  v1.RequestFailed,
+			wantEvents:  1,
+		},
+		{
+			name:   "no annotation",
+			tokens: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = machinev1beta1.AddToScheme(scheme)
+			machineObj := newMachine(tt.tokens[0])
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(machineObj).WithStatusSubresource(machineObj).Build()
+			carbideClient := &fakeRebootClient{err: tt.rebootErr}
+			recorder := record.NewFakeRecorder(10)
+			a := NewActuatorWithClient(fakeClient, recorder, carbideClient, "test-org")
+
+			for _, token := range tt.tokens {
+				machineObj.Annotations[RebootRequestAnnotation] = token
+				if err := a.Reboot(context.Background(), machineObj); err != nil {
+					t.Fatalf("Reboot() error = %v", err)
+				}
+			}
+
+			if carbideClient.reboots != tt.wantReboots {
+				t.Errorf("got %d reboots, want %d", carbideClient.reboots, tt.wantReboots)
+			}
+			if len(recorder.Events) != tt.wantEvents {
+				t.Errorf("got %d events, want %d", len(recorder.Events), tt.wantEvents)
+			}
+
+			got := &machinev1beta1.Machine{}
+			if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(machineObj), got); err != nil {
+				t.Fatal(err)
+			}
+			providerStatus, err := a.getProviderStatus(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantResult == "" {
+				if providerStatus.LastRebootRequest != nil {
+					t.Errorf("expected no reboot request, got %+v", providerStatus.LastRebootRequest)
+				}
+				return
+			}
+			last := providerStatus.LastRebootRequest
+			if last == nil || last.Token != tt.tokens[len(tt.tokens)-1] || last.Result != tt.wantResult {
+				t.Errorf("lastRebootRequest = %+v, want token %s and result %s",
+					last, tt.tokens[len(tt.tokens)-1], tt.wantResult)
+			}
+			if tt.rebootErr != nil && last.Message == "" {
+				t.Error("expected the failure message to be recorded")
+			}
+		})
+	}
+}
+
+// fakeReprovisionClient counts re-images and fails them with err
+type fakeReprovisionClient struct {
+	NvidiaCarbideClientInterface
+	requests []bmm.InstanceUpdateRequest
+	err      error
+}
+
+func (f *fakeReprovisionClient) UpdateInstance(
+	_ context.Context, _, instanceID string, req bmm.InstanceUpdateRequest,
+) (*bmm.Instance, *http.Response, error) {
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, &http.Response{StatusCode: http.StatusConflict}, f.err
+	}
+	return &bmm.Instance{Id: &instanceID}, &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func TestActuator_Reprovision(t *testing.T) {
+	providerSpec := &v1.NvidiaCarbideMachineProviderSpec{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       "NvidiaCarbideMachineProviderSpec",
+		},
+		SiteID:            "site",
+		BootSource:        v1.BootSource{OperatingSystemID: "os-2", UserData: "#cloud-config"},
+		CredentialsSecret: &corev1.SecretReference{Name: "creds"},
+	}
+	currentHash := bootSourceHash(providerSpec)
+
+	tests := []struct {
+		name          string
+		recordedHash  string
+		tokens        []string
+		reimageErr    error
+		wantReimages  int
+		wantToken     string
+		wantCondition string
+	}{
+		{
+			name:          "each token re-images once",
+			recordedHash:  currentHash,
+			tokens:        []string{"1", "1", "2"},
+			wantReimages:  2,
+			wantToken:     "2",
+			wantCondition: v1.ReprovisioningReason,
+		},
+		{
+			name:          "boot source change re-images once",
+			recordedHash:  "old",
+			tokens:        []string{"", ""},
+			wantReimages:  1,
+			wantToken:     bootSourceTokenPrefix + currentHash,
+			wantCondition: v1.ReprovisioningReason,
+		},
+		{
+			name:          "failed re-image is not retried",
+			recordedHash:  "old",
+			tokens:        []string{"", ""},
+			reimageErr:    errors.New("409 Conflict"),
+			wantReimages:  1,
+			wantToken:     bootSourceTokenPrefix + currentHash,
+			wantCondition: v1.ReprovisionFailedReason,
+		},
+		{
+			name:   "missing hash is recorded without re-imaging",
+			tokens: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specRaw, _ := json.Marshal(providerSpec)
+			statusRaw, _ := json.Marshal(&v1.NvidiaCarbideMachineProviderStatus{
+				InstanceID:     ptr("instance-1"),
+				BootSourceHash: tt.recordedHash,
+			})
+			machineObj := &machinev1beta1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "worker-0",
+					Namespace:   "default",
+					Annotations: map[string]string{ReprovisionRequestAnnotation: tt.tokens[0]},
+				},
+				Spec: machinev1beta1.MachineSpec{
+					ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{Raw: specRaw}},
+				},
+				Status: machinev1beta1.MachineStatus{ProviderStatus: &runtime.RawExtension{Raw: statusRaw}},
+			}
+			scheme := runtime.NewScheme()
+			_ = machinev1beta1.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(machineObj).WithStatusSubresource(machineObj).Build()
+			carbideClient := &fakeReprovisionClient{err: tt.reimageErr}
+			a := NewActuatorWithClient(fakeClient, record.NewFakeRecorder(10), carbideClient, "test-org")
+
+			for _, token := range tt.tokens {
+				machineObj.Annotations[ReprovisionRequestAnnotation] = token
+				if err := a.Reprovision(context.Background(), machineObj); err != nil {
+					t.Fatalf("Reprovision() error = %v", err)
+				}
+			}
+
+			if len(carbideClient.requests) != tt.wantReimages {
+				t.Fatalf("got %d re-images, want %d", len(carbideClient.requests), tt.wantReimages)
+			}
+			for _, req := range carbideClient.requests {
+				if os := req.OperatingSystemId.Get(); os == nil || *os != "os-2" {
+					t.Errorf("re-image operatingSystemId = %v, want os-2", os)
+				}
+			}
+
+			got := &machinev1beta1.Machine{}
+			if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(machineObj), got); err != nil {
+				t.Fatal(err)
+			}
+			providerStatus, err := a.getProviderStatus(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if providerStatus.BootSourceHash != currentHash {
+				t.Errorf("bootSourceHash = %q, want %q", providerStatus.BootSourceHash, currentHash)
+			}
+			last := providerStatus.LastReprovisionRequest
+			if tt.wantToken == "" {
+				if last != nil {
+					t.Errorf("expected no reprovision request, got %+v", last)
+				}
+				return
+			}
+			if last == nil || last.Token != tt.wantToken {
+				t.Errorf("lastReprovisionRequest = %+v, want token %s", last, tt.wantToken)
+			}
+			condition := meta.FindStatusCondition(providerStatus.Conditions, v1.ReprovisionedCondition)
+			if condition == nil || condition.Reason != tt.wantCondition {
+				t.Errorf("Reprovisioned condition = %+v, want reason %s", condition, tt.wantCondition)
+			}
+		})
+	}
+}
+
+func TestUpdateProvisionedTime(t *testing.T) {
+	provisioned := metav1.NewTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name           string
+		status         *v1.NvidiaCarbideMachineProviderStatus
+		instanceStatus bmm.InstanceStatus
+		wantObserved   bool
+		wantRecorded   bool
+	}{
+		{
+			name:           "first ready",
+			status:         &v1.NvidiaCarbideMachineProviderStatus{InstanceState: ptr("Provisioning")},
+			instanceStatus: bmm.INSTANCESTATUS_READY,
+			wantObserved:   true,
+			wantRecorded:   true,
+		},
+		{
+			name:           "still provisioning",
+			status:         &v1.NvidiaCarbideMachineProviderStatus{InstanceState: ptr("Pending")},
+			instanceStatus: bmm.INSTANCESTATUS_PROVISIONING,
+		},
+		{
+			name: "ready again after a reboot",
+			status: &v1.NvidiaCarbideMachineProviderStatus{
+				InstanceState:   ptr("Rebooting"),
+				ProvisionedTime: &provisioned,
+			},
+			instanceStatus: bmm.INSTANCESTATUS_READY,
+			wantRecorded:   true,
+		},
+		{
+			name:           "ready before the time was recorded",
+			status:         &v1.NvidiaCarbideMachineProviderStatus{InstanceState: ptr("Ready")},
+			instanceStatus: bmm.INSTANCESTATUS_READY,
+			wantRecorded:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := updateProvisionedTime(tt.status, tt.instanceStatus); got != tt.wantObserved {
+				t.Errorf("updateProvisionedTime() = %v, want %v", got, tt.wantObserved)
+			}
+			if recorded := tt.status.ProvisionedTime != nil; recorded != tt.wantRecorded {
+				t.Errorf("provisionedTime recorded = %v, want %v", recorded, tt.wantRecorded)
+			}
+		})
+	}
+}
+
+func TestReprovisionProgressCondition(t *testing.T) {
+	requested := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	before, after := requested.Add(-time.Hour), requested.Add(time.Hour)
+	history := func(created time.Time) []bmm.StatusDetail {
+		return []bmm.StatusDetail{{Status: ptr(string(bmm.INSTANCESTATUS_READY)), Created: &created}}
+	}
+
+	tests := []struct {
+		name       string
+		instance   *bmm.Instance
+		wantReason string
+	}{
+		{
+			name:     "still rebooting",
+			instance: &bmm.Instance{Status: ptr(bmm.INSTANCESTATUS_REBOOTING)},
+		},
+		{
+			name:     "update pending",
+			instance: &bmm.Instance{Status: ptr(bmm.INSTANCESTATUS_READY), IsUpdatePending: ptr(true)},
+		},
+		{
+			name:     "ready before the request",
+			instance: &bmm.Instance{Status: ptr(bmm.INSTANCESTATUS_READY), StatusHistory: history(before)},
+		},
+		{
+			name:       "ready after the request",
+			instance:   &bmm.Instance{Status: ptr(bmm.INSTANCESTATUS_READY), StatusHistory: history(after)},
+			wantReason: v1.ReprovisionedReason,
+		},
+		{
+			name:       "ready without history",
+			instance:   &bmm.Instance{Status: ptr(bmm.INSTANCESTATUS_READY)},
+			wantReason: v1.ReprovisionedReason,
+		},
+		{
+			name:       "error",
+			instance:   &bmm.Instance{Status: ptr(bmm.INSTANCESTATUS_ERROR)},
+			wantReason: v1.ReprovisionFailedReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := reprovisionProgressCondition("instance-1", tt.instance, requested)
+			if tt.wantReason == "" {
+				if condition != nil {
+					t.Errorf("expected no condition, got %+v", condition)
+				}
+				return
+			}
+			if condition == nil || condition.Reason != tt.wantReason {
+				t.Errorf("condition = %+v, want reason %s", condition, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestUpdateReprovisionProgress_Retried(t *testing.T) {
+	failed := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	retried := failed.Add(time.Hour)
+	readyAt := func(created time.Time) *bmm.Instance {
+		return &bmm.Instance{
+			Status:        ptr(bmm.INSTANCESTATUS_READY),
+			StatusHistory: []bmm.StatusDetail{{Status: ptr(string(bmm.INSTANCESTATUS_READY)), Created: &created}},
+		}
+	}
+	// A failed re-image followed by an accepted retry keeps the transition
+	// time of the failure on the Reprovisioned condition
+	newStatus := func() *v1.NvidiaCarbideMachineProviderStatus {
+		return &v1.NvidiaCarbideMachineProviderStatus{
+			InstanceID: ptr("instance-1"),
+			Conditions: []metav1.Condition{{
+				Type:               v1.ReprovisionedCondition,
+				Status:             metav1.ConditionFalse,
+				Reason:             v1.ReprovisioningReason,
+				LastTransitionTime: metav1.NewTime(failed),
+			}},
+			LastReprovisionRequest: &v1.RequestStatus{
+				Token:  "retry",
+				Result: v1.RequestSucceeded,
+				Time:   metav1.NewTime(retried),
+			},
+		}
+	}
+
+	status := newStatus()
+	if updateReprovisionProgress(status, readyAt(failed.Add(30*time.Minute))) {
+		t.Errorf("expected a Ready status between the failure and the retry to be ignored, got %+v", status.Conditions)
+	}
+
+	status = newStatus()
+	if !updateReprovisionProgress(status, readyAt(retried.Add(30*time.Minute))) {
+		t.Fatal("expected a Ready status after the retry to complete the re-image")
+	}
+	condition := meta.FindStatusCondition(status.Conditions, v1.ReprovisionedCondition)
+	if condition.Reason != v1.ReprovisionedReason {
+		t.Errorf("condition reason = %s, want %s", condition.Reason, v1.ReprovisionedReason)
+	}
+}
+
+func TestDeletionProtectionLabels(t *testing.T) {
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		protected bool
+		want      map[string]string
+	}{
+		{
+			name:      "protect",
+			labels:    map[string]string{"team": "ml"},
+			protected: true,
+			want:      map[string]string{"team": "ml", DeletionProtectionInstanceLabel: "true"},
+		},
+		{
+			name:      "protect without labels",
+			protected: true,
+			want:      map[string]string{DeletionProtectionInstanceLabel: "true"},
+		},
+		{
+			name:      "already protected",
+			labels:    map[string]string{DeletionProtectionInstanceLabel: "true"},
+			protected: true,
+		},
+		{
+			name:   "unprotect",
+			labels: map[string]string{"team": "ml", DeletionProtectionInstanceLabel: "true"},
+			want:   map[string]string{"team": "ml", DeletionProtectionInstanceLabel: "false"},
+		},
+		{
+			name:   "never protected",
+			labels: map[string]string{"team": "ml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := maps.Clone(tt.labels)
+			got := deletionProtectionLabels(tt.labels, tt.protected)
+			if !maps.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("deletionProtectionLabels() = %v, want %v", got, tt.want)
+			}
+			if !maps.Equal(tt.labels, original) {
+				t.Errorf("input labels changed to %v", tt.labels)
+			}
+		})
+	}
+}
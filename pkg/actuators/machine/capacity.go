@@ -0,0 +1,132 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
+)
+
+// InsufficientCapacityMachineError is the Machine error reason set when the
+// site has no free host of the requested instance type
+const InsufficientCapacityMachineError machinev1beta1.MachineStatusError = "InsufficientCapacity"
+
+// InsufficientCapacityError is returned by Create when the site has no free
+// host of the instance type
+type InsufficientCapacityError struct {
+	SiteID         string
+	InstanceTypeID string
+}
+
+func (e *InsufficientCapacityError) Error() string {
+	return fmt.Sprintf("no host of instance type %s is available in site %s", e.InstanceTypeID, e.SiteID)
+}
+
+// IsInsufficientCapacity reports whether err is an InsufficientCapacityError
+func IsInsufficientCapacity(err error) bool {
+	return errors.As(err, new(*InsufficientCapacityError))
+}
+
+// checkCapacity returns an InsufficientCapacityError when the allocation
+// statistics of the instance type show no host that can be provisioned. The
+// instance type is read again rather than taken from the pre-flight cache, so
+// that Machines created in a burst do not all pass on one stale snapshot.
+// Lookup failures other than rejected credentials are logged and let the create
+// proceed, so that Carbide remains the authority on capacity.
+func (a *Actuator) checkCapacity(
+	ctx context.Context,
+	nvidiaCarbideClient NvidiaCarbideClientInterface,
+	orgName string,
+	providerSpec *v1.NvidiaCarbideMachineProviderSpec,
+) error {
+	// A specific machine is requested, the instance type pool does not apply
+	if providerSpec.InstanceTypeID == "" || providerSpec.MachineID != "" {
+		return nil
+	}
+
+	instanceType, _, err := nvidiaCarbideClient.GetInstanceType(ctx, orgName, providerSpec.InstanceTypeID)
+	if err != nil {
+		if errors.As(err, new(*CredentialsRejectedError)) {
+			return fmt.Errorf("failed to get instance type %s: %w", providerSpec.InstanceTypeID, err)
+		}
+		log.FromContext(ctx).Error(err, "failed to check instance type capacity, creating anyway",
+			"instanceTypeId", providerSpec.InstanceTypeID)
+		return nil
+	}
+	return instanceTypeCapacityError(providerSpec, instanceType)
+}
+
+// instanceTypeCapacityError returns an InsufficientCapacityError when the
+// instance type has no host that can be provisioned
+func instanceTypeCapacityError(providerSpec *v1.NvidiaCarbideMachineProviderSpec, instanceType *bmm.InstanceType) error {
+	if !hasAvailableCapacity(instanceType) {
+		return &InsufficientCapacityError{
+			SiteID:         providerSpec.SiteID,
+			InstanceTypeID: providerSpec.InstanceTypeID,
+		}
+	}
+	return nil
+}
+
+// hasAvailableCapacity reports whether an instance type has a host in Ready
+// state allocated to the tenant. Missing statistics count as available.
+func hasAvailableCapacity(instanceType *bmm.InstanceType) bool {
+	if instanceType == nil || instanceType.AllocationStats == nil || instanceType.AllocationStats.UnusedUsable == nil {
+		return true
+	}
+	return *instanceType.AllocationStats.UnusedUsable > 0
+}
+
+// reportInsufficientCapacity records a failed capacity check on the Machine
+func (a *Actuator) reportInsufficientCapacity(ctx context.Context, machineObj client.Object, err error) {
+	capacityErr := &InsufficientCapacityError{}
+	if !errors.As(err, &capacityErr) {
+		return
+	}
+
+	if a.eventRecorder != nil {
+		a.eventRecorder.Event(machineObj, corev1.EventTypeWarning, v1.InsufficientCapacityReason, capacityErr.Error())
+	}
+	if condErr := a.setCondition(machineObj, metav1.Condition{
+		Type:    v1.CapacityAvailableCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1.InsufficientCapacityReason,
+		Message: capacityErr.Error(),
+	}); condErr != nil {
+		log.FromContext(ctx).Error(condErr, "failed to set capacity condition",
+			"machine", machineObj.GetName())
+	}
+}
+
+// capacityAvailableCondition is reported once an instance was created
+func capacityAvailableCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:   v1.CapacityAvailableCondition,
+		Status: metav1.ConditionTrue,
+		Reason: v1.CapacityAvailableReason,
+	}
+}
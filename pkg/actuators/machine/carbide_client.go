@@ -0,0 +1,317 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/metrics"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/tracing"
+	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
+)
+
+// CredentialsRejectedError reports that Carbide rejected the token from a
+// credentials Secret, even after the Secret was re-read
+type CredentialsRejectedError struct {
+	// SecretKey identifies the credentials Secret
+	SecretKey client.ObjectKey
+	// Err is the error returned by the rejected request
+	Err error
+}
+
+func (e *CredentialsRejectedError) Error() string {
+	return fmt.Sprintf("NVIDIA Carbide rejected the credentials from secret %s: %v", e.SecretKey, e.Err)
+}
+
+func (e *CredentialsRejectedError) Unwrap() error {
+	return e.Err
+}
+
+// credentials holds the contents of a credentials Secret
+type credentials struct {
+	endpoint string
+	orgName  string
+	token    string
+}
+
+// readCredentials reads and validates a credentials Secret. Errors never
+// include Secret values.
+func readCredentials(ctx context.Context, reader client.Reader, secretKey client.ObjectKey) (*credentials, error) {
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, secretKey, secret); err != nil {
+		return nil, fmt.Errorf("failed to get credentials secret: %w", err)
+	}
+
+	// Validate secret contains required fields
+	endpoint, ok := secret.Data["endpoint"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s is missing 'endpoint' field", secretKey.Name)
+	}
+	orgName, ok := secret.Data["orgName"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s is missing 'orgName' field", secretKey.Name)
+	}
+	token, ok := secret.Data["token"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s is missing 'token' field", secretKey.Name)
+	}
+
+	return &credentials{
+		endpoint: string(endpoint),
+		orgName:  string(orgName),
+		token:    string(token),
+	}, nil
+}
+
+// carbideClient wraps the SDK APIClient and injects auth context. A request
+// rejected with 401 is retried once after re-reading the credentials Secret.
+// It is safe for concurrent use.
+type carbideClient struct {
+	secretKey client.ObjectKey
+	reader    client.Reader
+
+	// mu guards the credentials and the API client built for their endpoint,
+	// which are replaced when the Secret is rotated
+	mu     sync.RWMutex
+	client *bmm.APIClient
+	creds  credentials
+}
+
+// newAPIClient returns an SDK client for a Carbide endpoint
+func newAPIClient(endpoint string) *bmm.APIClient {
+	sdkCfg := bmm.NewConfiguration()
+	sdkCfg.Servers = bmm.ServerConfigurations{
+		{URL: endpoint},
+	}
+	sdkCfg.HTTPClient = tracing.NewHTTPClient()
+	return bmm.NewAPIClient(sdkCfg)
+}
+
+func newCarbideClient(creds *credentials, secretKey client.ObjectKey, reader client.Reader) *carbideClient {
+	return &carbideClient{
+		client:    newAPIClient(creds.endpoint),
+		creds:     *creds,
+		secretKey: secretKey,
+		reader:    reader,
+	}
+}
+
+// NewNvidiaCarbideClientForSecret returns a client using the credentials
+// Secret secretKey, along with the organization name it holds. The Secret is
+// re-read through reader when Carbide rejects its token.
+func NewNvidiaCarbideClientForSecret(
+	ctx context.Context, reader client.Reader, secretKey client.ObjectKey,
+) (NvidiaCarbideClientInterface, string, error) {
+	creds, err := readCredentials(ctx, reader, secretKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return newCarbideClient(creds, secretKey, reader), creds.orgName, nil
+}
+
+// current returns the API client and the credentials it is used with
+func (c *carbideClient) current() (*bmm.APIClient, credentials) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client, c.creds
+}
+
+// rotate replaces the credentials, building a new API client when the
+// endpoint changed
+func (c *carbideClient) rotate(creds *credentials) *bmm.APIClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if creds.endpoint != c.creds.endpoint {
+		c.client = newAPIClient(creds.endpoint)
+	}
+	c.creds = *creds
+	return c.client
+}
+
+func authCtx(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, bmm.ContextAccessToken, token)
+}
+
+// do runs call with the current token, recording its latency under operation.
+// On 401 the Secret is re-read and the call retried once, against the new
+// endpoint if it changed. A second 401, or unchanged credentials, is returned
+// as a CredentialsRejectedError. A changed organization is not retried, as
+// the request names the previous one.
+func (c *carbideClient) do(
+	ctx context.Context, operation string,
+	call func(authCtx context.Context, api *bmm.APIClient) (*http.Response, error),
+) (*http.Response, error) {
+	api, used := c.current()
+	start := time.Now()
+	httpResp, err := call(authCtx(ctx, used.token), api)
+	metrics.ObserveAPIRequest(operation, start, httpResp)
+	if !isUnauthorized(httpResp) {
+		return httpResp, err
+	}
+
+	logger := log.FromContext(ctx).WithValues("secret", c.secretKey.String(), "operation", operation)
+	logger.Info("NVIDIA Carbide rejected the token, re-reading credentials secret")
+
+	creds, readErr := readCredentials(ctx, c.reader, c.secretKey)
+	if readErr != nil {
+		return httpResp, fmt.Errorf("failed to re-read credentials secret %s: %w", c.secretKey, readErr)
+	}
+	if *creds == used {
+		return httpResp, &CredentialsRejectedError{SecretKey: c.secretKey, Err: err}
+	}
+	api = c.rotate(creds)
+	if creds.orgName != used.orgName {
+		return httpResp, fmt.Errorf("credentials secret %s changed organization from %s to %s, "+
+			"%s is not retried for the previous organization", c.secretKey, used.orgName, creds.orgName, operation)
+	}
+
+	start = time.Now()
+	httpResp, err = call(authCtx(ctx, creds.token), api)
+	metrics.ObserveAPIRequest(operation, start, httpResp)
+	if isUnauthorized(httpResp) {
+		return httpResp, &CredentialsRejectedError{SecretKey: c.secretKey, Err: err}
+	}
+	return httpResp, err
+}
+
+func (c *carbideClient) CreateInstance(
+	ctx context.Context, org string, req bmm.InstanceCreateRequest,
+) (*bmm.Instance, *http.Response, error) {
+	var instance *bmm.Instance
+	httpResp, err := c.do(ctx, "CreateInstance", func(authCtx context.Context, api *bmm.APIClient) (*http.Response, error) {
+		var httpResp *http.Response
+		var err error
+		instance, httpResp, err = api.InstanceAPI.CreateInstance(authCtx, org).InstanceCreateRequest(req).Execute()
+		return httpResp, err
+	})
+	return instance, httpResp, err
+}
+
+func (c *carbideClient) GetInstance(
+	ctx context.Context, org, instanceId string,
+) (*bmm.Instance, *http.Response, error) {
+	var instance *bmm.Instance
+	httpResp, err := c.do(ctx, "GetInstance", func(authCtx context.Context, api *bmm.APIClient) (*http.Response, error) {
+		var httpResp *http.Response
+		var err error
+		instance, httpResp, err = api.InstanceAPI.GetInstance(authCtx, org, instanceId).Execute()
+		return httpResp, err
+	})
+	return instance, httpResp, err
+}
+
+func (c *carbideClient) DeleteInstance(ctx context.Context, org, instanceId string) (*http.Response, error) {
+	return c.do(ctx, "DeleteInstance", func(authCtx context.Context, api *bmm.APIClient) (*http.Response, error) {
+		return api.InstanceAPI.DeleteInstance(authCtx, org, instanceId).Execute()
+	})
+}
+
+// RebootInstance power cycles an instance
+func (c *carbideClient) RebootInstance(ctx context.Context, org, instanceId string) (*http.Response, error) {
+	req := bmm.InstanceUpdateRequest{TriggerReboot: *bmm.NewNullableBool(ptr(true))}
+	return c.do(ctx, "RebootInstance", func(authCtx context.Context, api *bmm.APIClient) (*http.Response, error) {
+		_, httpResp, err := api.InstanceAPI.UpdateInstance(authCtx, org, instanceId).InstanceUpdateRequest(req).Execute()
+		return httpResp, err
+	})
+}
+
+func (c *carbideClient) UpdateInstance(
+	ctx context.Context, org, instanceId string, req bmm.InstanceUpdateRequest,
+) (*bmm.Instance, *http.Response, error) {
+	var instance *bmm.Instance
+	httpResp, err := c.do(ctx, "UpdateInstance", func(authCtx context.Context, api *bmm.APIClient) (*http.Response, error) {
+		var httpResp *http.Response
+		var err error
+		instance, httpResp, err = api.InstanceAPI.UpdateInstance(authCtx, org, instanceId).
+			InstanceUpdateRequest(req).Execute()
+		return httpResp, err
+	})
+	return instance, httpResp, err
+}
+
+func (c *carbideClient) GetInstanceType(
+	ctx context.Context, org, instanceTypeId string,
+) (*bmm.InstanceType, *http.Response, error) {
+	var instanceType *bmm.InstanceType
+	httpResp, err := c.do(ctx, "GetInstanceType", func(authCtx context.Context, api *bmm.APIClient) (*http.Response, error) {
+		var httpResp *http.Response
+		var err error
+		instanceType, httpResp, err = api.InstanceTypeAPI.GetInstanceType(authCtx, org, instanceTypeId).
+			IncludeAllocationStats(true).
+			Execute()
+		return httpResp, err
+	})
+	return instanceType, httpResp, err
+}
+
+func (c *carbideClient) GetSite(ctx context.Context, org, siteId string) (*bmm.Site, *http.Response, error) {
+	var site *bmm.Site
+	httpResp, err := c.do(ctx, "GetSite", func(authCtx context.Context, api *bmm.APIClient) (*http.Response, error) {
+		var httpResp *http.Response
+		var err error
+		site, httpResp, err = api.SiteAPI.GetSite(authCtx, org, siteId).Execute()
+		return httpResp, err
+	})
+	return site, httpResp, err
+}
+
+func (c *carbideClient) GetVpc(ctx context.Context, org, vpcId string) (*bmm.VPC, *http.Response, error) {
+	var vpc *bmm.VPC
+	httpResp, err := c.do(ctx, "GetVpc", func(authCtx context.Context, api *bmm.APIClient) (*http.Response, error) {
+		var httpResp *http.Response
+		var err error
+		vpc, httpResp, err = api.VPCAPI.GetVpc(authCtx, org, vpcId).Execute()
+		return httpResp, err
+	})
+	return vpc, httpResp, err
+}
+
+func (c *carbideClient) GetSubnet(ctx context.Context, org, subnetId string) (*bmm.Subnet, *http.Response, error) {
+	var subnet *bmm.Subnet
+	httpResp, err := c.do(ctx, "GetSubnet", func(authCtx context.Context, api *bmm.APIClient) (*http.Response, error) {
+		var httpResp *http.Response
+		var err error
+		subnet, httpResp, err = api.SubnetAPI.GetSubnet(authCtx, org, subnetId).Execute()
+		return httpResp, err
+	})
+	return subnet, httpResp, err
+}
+
+func (c *carbideClient) GetSSHKeyGroup(
+	ctx context.Context, org, sshKeyGroupId string,
+) (*bmm.SshKeyGroup, *http.Response, error) {
+	var sshKeyGroup *bmm.SshKeyGroup
+	httpResp, err := c.do(ctx, "GetSSHKeyGroup", func(authCtx context.Context, api *bmm.APIClient) (*http.Response, error) {
+		var httpResp *http.Response
+		var err error
+		sshKeyGroup, httpResp, err = api.SSHKeyGroupAPI.GetSshKeyGroup(authCtx, org, sshKeyGroupId).Execute()
+		return httpResp, err
+	})
+	return sshKeyGroup, httpResp, err
+}
+
+func isUnauthorized(httpResp *http.Response) bool {
+	return httpResp != nil && httpResp.StatusCode == http.StatusUnauthorized
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// setCondition sets a condition in the Machine provider status, writing the
+// status only when the condition changed
+func (a *Actuator) setCondition(machineObj client.Object, condition metav1.Condition) error {
+	providerStatus, err := a.getProviderStatus(machineObj)
+	if err != nil {
+		return err
+	}
+	if !meta.SetStatusCondition(&providerStatus.Conditions, condition) {
+		return nil
+	}
+	return a.setProviderStatus(machineObj, providerStatus)
+}
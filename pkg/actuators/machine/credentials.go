@@ -0,0 +1,158 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+)
+
+// CredentialsPolicy controls which credentials Secrets the provider may read
+type CredentialsPolicy struct {
+	// DefaultSecret is used when the provider spec does not name a credentials Secret
+	DefaultSecret *client.ObjectKey
+
+	// AllowedNamespaces lists the namespaces credentials Secrets may be read from.
+	// An empty list allows every namespace.
+	AllowedNamespaces []string
+}
+
+// CredentialsPolicyError reports a credentials Secret reference rejected by the policy
+type CredentialsPolicyError struct {
+	// Reason is the condition reason describing the rejection
+	Reason string
+	// Message is a human-readable description of the rejection
+	Message string
+}
+
+func (e *CredentialsPolicyError) Error() string {
+	return e.Message
+}
+
+// ParseObjectKey parses a "namespace/name" object reference
+func ParseObjectKey(value string) (client.ObjectKey, error) {
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return client.ObjectKey{}, fmt.Errorf("invalid reference %q, expected namespace/name", value)
+	}
+	return client.ObjectKey{Namespace: namespace, Name: name}, nil
+}
+
+// ParseCredentialsSecretKey parses a "namespace/name" Secret reference
+func ParseCredentialsSecretKey(value string) (client.ObjectKey, error) {
+	key, err := ParseObjectKey(value)
+	if err != nil {
+		return client.ObjectKey{}, fmt.Errorf("invalid credentials secret: %w", err)
+	}
+	return key, nil
+}
+
+// resolve returns the Secret to read credentials from for a Machine in
+// machineNamespace. An empty reference falls back to the default Secret and an
+// empty namespace to the Machine namespace.
+func (p CredentialsPolicy) resolve(
+	machineNamespace string, ref *corev1.SecretReference,
+) (client.ObjectKey, error) {
+	var key client.ObjectKey
+	switch {
+	case ref != nil && ref.Name != "":
+		key = client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+		if key.Namespace == "" {
+			key.Namespace = machineNamespace
+		}
+	case p.DefaultSecret != nil:
+		key = *p.DefaultSecret
+	default:
+		return client.ObjectKey{}, &CredentialsPolicyError{
+			Reason:  v1.CredentialsSecretNotSpecifiedReason,
+			Message: "providerSpec.credentialsSecret is not set and no default credentials secret is configured",
+		}
+	}
+
+	if len(p.AllowedNamespaces) > 0 && !slices.Contains(p.AllowedNamespaces, key.Namespace) {
+		return client.ObjectKey{}, &CredentialsPolicyError{
+			Reason: v1.CredentialsSecretNotAllowedReason,
+			Message: fmt.Sprintf("credentials secret %s is in namespace %q, which is not one of the allowed namespaces [%s]",
+				key.String(), key.Namespace, strings.Join(p.AllowedNamespaces, ", ")),
+		}
+	}
+
+	return key, nil
+}
+
+// rejectCredentials reports a credentials policy violation on the Machine
+func (a *Actuator) rejectCredentials(ctx context.Context, machineObj client.Object, err error) {
+	policyErr := &CredentialsPolicyError{}
+	if !errors.As(err, &policyErr) {
+		return
+	}
+
+	if a.eventRecorder != nil {
+		a.eventRecorder.Event(machineObj, corev1.EventTypeWarning, policyErr.Reason, policyErr.Message)
+	}
+	if condErr := a.setCondition(machineObj, metav1.Condition{
+		Type:    v1.CredentialsValidCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  policyErr.Reason,
+		Message: policyErr.Message,
+	}); condErr != nil {
+		log.FromContext(ctx).Error(condErr, "failed to set credentials condition",
+			"machine", machineObj.GetName())
+	}
+}
+
+// reportCredentialsRejected reports a token rejected by Carbide on the Machine,
+// naming the credentials Secret but never its contents
+func (a *Actuator) reportCredentialsRejected(ctx context.Context, machineObj client.Object, err error) {
+	rejectedErr := &CredentialsRejectedError{}
+	if !errors.As(err, &rejectedErr) {
+		return
+	}
+
+	message := fmt.Sprintf("NVIDIA Carbide rejected the token in credentials secret %s", rejectedErr.SecretKey)
+	if a.eventRecorder != nil {
+		a.eventRecorder.Event(machineObj, corev1.EventTypeWarning, v1.CredentialsRejectedReason, message)
+	}
+	if condErr := a.setCondition(machineObj, metav1.Condition{
+		Type:    v1.CredentialsValidCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1.CredentialsRejectedReason,
+		Message: message,
+	}); condErr != nil {
+		log.FromContext(ctx).Error(condErr, "failed to set credentials condition",
+			"machine", machineObj.GetName())
+	}
+}
+
+// credentialsAcceptedCondition is reported once Carbide accepted a request
+func credentialsAcceptedCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:   v1.CredentialsValidCondition,
+		Status: metav1.ConditionTrue,
+		Reason: v1.CredentialsAcceptedReason,
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
+)
+
+const (
+	// DeletionProtectionAnnotation protects a Machine from deletion when set
+	// to "true", like the deletionProtection provider spec field
+	DeletionProtectionAnnotation = "nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/deletion-protection"
+
+	// DeletionProtectionInstanceLabel is the Carbide instance label mirroring
+	// the deletion protection of its Machine
+	DeletionProtectionInstanceLabel = "openshift-deletion-protection"
+)
+
+// DeletionProtected reports whether the provider spec or the annotations of
+// a Machine protect its instance from deletion
+func (a *Actuator) DeletionProtected(machine runtime.Object) (bool, error) {
+	machineObj, ok := machine.(client.Object)
+	if !ok {
+		return false, fmt.Errorf("machine is not a client.Object")
+	}
+	providerSpec, err := a.getProviderSpec(machineObj)
+	if err != nil {
+		return false, fmt.Errorf("failed to get provider spec: %w", err)
+	}
+	return deletionProtected(machineObj, providerSpec), nil
+}
+
+// deletionProtected reports whether a Machine is protected from deletion
+func deletionProtected(machineObj client.Object, providerSpec *v1.NvidiaCarbideMachineProviderSpec) bool {
+	annotated, _ := strconv.ParseBool(machineObj.GetAnnotations()[DeletionProtectionAnnotation])
+	return annotated || providerSpec.DeletionProtection
+}
+
+// deletionProtectionLabels returns the instance labels with the deletion
+// protection label set to protected, or nil when they already match. An
+// update replaces all labels and ignores an empty map, so an unprotected
+// instance keeps the label set to false rather than losing it.
+func deletionProtectionLabels(labels map[string]string, protected bool) map[string]string {
+	value := strconv.FormatBool(protected)
+	current, ok := labels[DeletionProtectionInstanceLabel]
+	if current == value || (!ok && !protected) {
+		return nil
+	}
+	updated := maps.Clone(labels)
+	if updated == nil {
+		updated = map[string]string{}
+	}
+	updated[DeletionProtectionInstanceLabel] = value
+	return updated
+}
+
+// syncDeletionProtectionLabel mirrors the deletion protection of a Machine
+// to the labels of its instance. Failures are reported but do not fail the
+// reconciliation, the label is informational.
+func (a *Actuator) syncDeletionProtectionLabel(
+	ctx context.Context, nvidiaCarbideClient NvidiaCarbideClientInterface, orgName string,
+	machineObj client.Object, providerSpec *v1.NvidiaCarbideMachineProviderSpec, instance *bmm.Instance,
+) {
+	labels := deletionProtectionLabels(instance.Labels, deletionProtected(machineObj, providerSpec))
+	if labels == nil || instance.Id == nil {
+		return
+	}
+	_, _, err := nvidiaCarbideClient.UpdateInstance(ctx, orgName, *instance.Id, bmm.InstanceUpdateRequest{Labels: labels})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to update deletion protection label", "instanceID", *instance.Id)
+		a.reportCredentialsRejected(ctx, machineObj, err)
+		if a.eventRecorder != nil {
+			a.eventRecorder.Eventf(machineObj, corev1.EventTypeWarning, "FailedUpdate",
+				"Failed to label instance %s with its deletion protection: %v", *instance.Id, err)
+		}
+		return
+	}
+	instance.Labels = labels
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
+)
+
+// ImmutableFields are the JSON names of the provider spec fields that cannot
+// be applied to a provisioned instance. The subnet IDs are those of interfaces.
+var ImmutableFields = []string{
+	"siteId", "tenantId", "vpcId", "interfaces", "instanceTypeId", "machineId",
+}
+
+// ImmutableFieldChanges returns the JSON names of the provider spec fields that
+// cannot be applied to a provisioned instance and differ between two provider
+// specs
+func ImmutableFieldChanges(oldSpec, newSpec *v1.NvidiaCarbideMachineProviderSpec) []string {
+	var changed []string
+	if oldSpec.SiteID != newSpec.SiteID {
+		changed = append(changed, "siteId")
+	}
+	if oldSpec.TenantID != newSpec.TenantID {
+		changed = append(changed, "tenantId")
+	}
+	if oldSpec.VpcID != newSpec.VpcID {
+		changed = append(changed, "vpcId")
+	}
+	if !slices.Equal(oldSpec.Interfaces, newSpec.Interfaces) {
+		changed = append(changed, "interfaces")
+	}
+	if oldSpec.InstanceTypeID != newSpec.InstanceTypeID {
+		changed = append(changed, "instanceTypeId")
+	}
+	if oldSpec.MachineID != newSpec.MachineID {
+		changed = append(changed, "machineId")
+	}
+	return changed
+}
+
+// ImmutableFieldsMessage explains that the changed fields cannot be changed in
+// place, listing every immutable field so that all of them are known upfront
+func ImmutableFieldsMessage(changed, immutable []string) string {
+	return fmt.Sprintf("%s cannot be changed once the instance is provisioned, replace the Machine instead "+
+		"(immutable fields: %s)", strings.Join(changed, ", "), strings.Join(immutable, ", "))
+}
+
+// instanceFieldDrift returns the JSON names of the immutable provider spec
+// fields that no longer match the provisioned instance. Fields the instance
+// does not report are not compared.
+func instanceFieldDrift(spec *v1.NvidiaCarbideMachineProviderSpec, instance *bmm.Instance) []string {
+	var drift []string
+	if instance.SiteId != nil && *instance.SiteId != spec.SiteID {
+		drift = append(drift, "siteId")
+	}
+	if instance.TenantId != nil && *instance.TenantId != spec.TenantID {
+		drift = append(drift, "tenantId")
+	}
+	if instance.VpcId != nil && *instance.VpcId != spec.VpcID {
+		drift = append(drift, "vpcId")
+	}
+
+	var instanceSubnets []string
+	for _, iface := range instance.Interfaces {
+		if subnetID := iface.SubnetId.Get(); subnetID != nil {
+			instanceSubnets = append(instanceSubnets, *subnetID)
+		}
+	}
+	if len(instanceSubnets) > 0 {
+		specSubnets := spec.SubnetIDs()
+		slices.Sort(specSubnets)
+		slices.Sort(instanceSubnets)
+		if !slices.Equal(specSubnets, instanceSubnets) {
+			drift = append(drift, "interfaces")
+		}
+	}
+
+	if spec.InstanceTypeID != "" && instance.InstanceTypeId != nil && *instance.InstanceTypeId != spec.InstanceTypeID {
+		drift = append(drift, "instanceTypeId")
+	}
+	if machineID := instance.MachineId.Get(); spec.MachineID != "" && machineID != nil && *machineID != spec.MachineID {
+		drift = append(drift, "machineId")
+	}
+	return drift
+}
+
+// providerSpecAppliedCondition reports whether the provider spec matches the
+// provisioned instance
+func providerSpecAppliedCondition(drift []string) metav1.Condition {
+	if len(drift) == 0 {
+		return metav1.Condition{
+			Type:   v1.ProviderSpecAppliedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: v1.ProviderSpecAppliedReason,
+		}
+	}
+	return metav1.Condition{
+		Type:    v1.ProviderSpecAppliedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1.ImmutableFieldChangedReason,
+		Message: ImmutableFieldsMessage(drift, ImmutableFields),
+	}
+}
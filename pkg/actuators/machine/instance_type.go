@@ -0,0 +1,173 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
+)
+
+// Carbide machine capability types
+const (
+	capabilityTypeCPU    = "CPU"
+	capabilityTypeMemory = "Memory"
+	capabilityTypeGPU    = "GPU"
+)
+
+// InstanceTypeCapacity summarizes the resources of one host of an instance type
+type InstanceTypeCapacity struct {
+	// VCPU is the number of hardware threads
+	VCPU int64
+	// MemoryMiB is the total memory in MiB
+	MemoryMiB int64
+	// GPU is the number of GPUs
+	GPU int64
+}
+
+// InstanceTypeKey identifies an instance type as seen through one credentials
+// Secret. Lookups made with different organizations or credentials must not be
+// shared, since either may not be allowed to see the instance type.
+type InstanceTypeKey struct {
+	OrgName           string
+	CredentialsSecret client.ObjectKey
+	InstanceTypeID    string
+}
+
+// GetInstanceTypeKey returns the key of the instance type referenced by
+// providerSpec, using the credentials Secret as resolved for an object in
+// namespace
+func (a *Actuator) GetInstanceTypeKey(
+	ctx context.Context, namespace string, providerSpec *v1.NvidiaCarbideMachineProviderSpec,
+) (InstanceTypeKey, error) {
+	secretKey, err := a.credentialsPolicy.resolve(namespace, providerSpec.CredentialsSecret)
+	if err != nil {
+		return InstanceTypeKey{}, err
+	}
+
+	orgName := a.orgName
+	if a.nvidiaCarbideClient == nil {
+		creds, err := readCredentials(ctx, a.client, secretKey)
+		if err != nil {
+			return InstanceTypeKey{}, err
+		}
+		orgName = creds.orgName
+	}
+
+	return InstanceTypeKey{
+		OrgName:           orgName,
+		CredentialsSecret: secretKey,
+		InstanceTypeID:    providerSpec.InstanceTypeID,
+	}, nil
+}
+
+// GetInstanceTypeCapacity looks up the CPU, memory and GPU capabilities of the
+// instance type referenced by providerSpec, using the credentials Secret as
+// resolved for an object in namespace
+func (a *Actuator) GetInstanceTypeCapacity(
+	ctx context.Context, namespace string, providerSpec *v1.NvidiaCarbideMachineProviderSpec,
+) (*InstanceTypeCapacity, error) {
+	if providerSpec.InstanceTypeID == "" {
+		return nil, fmt.Errorf("providerSpec.instanceTypeId is not set")
+	}
+
+	nvidiaCarbideClient, orgName, err := a.newNvidiaCarbideClient(ctx, namespace, providerSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NVIDIA Carbide client: %w", err)
+	}
+
+	instanceType, httpResp, err := nvidiaCarbideClient.GetInstanceType(ctx, orgName, providerSpec.InstanceTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance type %s: %w", providerSpec.InstanceTypeID, err)
+	}
+	if instanceType == nil {
+		return nil, fmt.Errorf("get instance type returned no data, status code: %d", httpResp.StatusCode)
+	}
+
+	return capacityFromInstanceType(instanceType)
+}
+
+// capacityFromInstanceType sums the machine capabilities of an instance type.
+// CPU capabilities count sockets, each with Threads (or Cores) hardware threads;
+// memory capabilities count DIMMs of Capacity each.
+func capacityFromInstanceType(instanceType *bmm.InstanceType) (*InstanceTypeCapacity, error) {
+	capacity := &InstanceTypeCapacity{}
+
+	for _, capability := range instanceType.MachineCapabilities {
+		if capability.Type == nil {
+			continue
+		}
+
+		count := int64(1)
+		if c := capability.Count.Get(); c != nil {
+			count = int64(*c)
+		}
+
+		switch {
+		case strings.EqualFold(*capability.Type, capabilityTypeCPU):
+			threads := int64(1)
+			if t := capability.Threads.Get(); t != nil && *t > 0 {
+				threads = int64(*t)
+			} else if c := capability.Cores.Get(); c != nil && *c > 0 {
+				threads = int64(*c)
+			}
+			capacity.VCPU += count * threads
+		case strings.EqualFold(*capability.Type, capabilityTypeMemory):
+			size := capability.Capacity.Get()
+			if size == nil {
+				continue
+			}
+			mib, err := parseMemoryMiB(*size)
+			if err != nil {
+				return nil, err
+			}
+			capacity.MemoryMiB += count * mib
+		case strings.EqualFold(*capability.Type, capabilityTypeGPU):
+			capacity.GPU += count
+		}
+	}
+
+	return capacity, nil
+}
+
+// parseMemoryMiB parses a Carbide memory capacity such as "64GB", "64 GiB" or
+// "1TB" into MiB. Decimal units are read as their binary counterparts, as
+// vendors report DIMM sizes that way.
+func parseMemoryMiB(value string) (int64, error) {
+	normalized := strings.ReplaceAll(value, " ", "")
+	for _, unit := range []struct{ from, to string }{
+		{"KiB", "Ki"}, {"MiB", "Mi"}, {"GiB", "Gi"}, {"TiB", "Ti"},
+		{"KB", "Ki"}, {"MB", "Mi"}, {"GB", "Gi"}, {"TB", "Ti"},
+	} {
+		if strings.HasSuffix(strings.ToUpper(normalized), strings.ToUpper(unit.from)) {
+			normalized = normalized[:len(normalized)-len(unit.from)] + unit.to
+			break
+		}
+	}
+
+	quantity, err := resource.ParseQuantity(normalized)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory capacity %q: %w", value, err)
+	}
+	return quantity.Value() / (1024 * 1024), nil
+}
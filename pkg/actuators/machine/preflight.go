@@ -0,0 +1,286 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+)
+
+// preflightCacheTTL bounds how long a resolved reference is trusted, so that
+// MachineSets scaling up do not issue the same lookups for every Machine
+const preflightCacheTTL = time.Minute
+
+// InvalidConfigurationError is returned by Create when the provider spec
+// references Carbide resources that do not exist or do not belong together
+type InvalidConfigurationError struct {
+	Problems []string
+}
+
+func (e *InvalidConfigurationError) Error() string {
+	return "invalid provider spec: " + strings.Join(e.Problems, "; ")
+}
+
+// IsInvalidConfiguration reports whether err is an InvalidConfigurationError
+func IsInvalidConfiguration(err error) bool {
+	return errors.As(err, new(*InvalidConfigurationError))
+}
+
+// preflightKey identifies a lookup. Lookups made with different credentials
+// Secrets are not shared, since either may not be allowed to see the resource.
+type preflightKey struct {
+	kind              string
+	orgName           string
+	credentialsSecret client.ObjectKey
+	id                string
+}
+
+type preflightEntry struct {
+	// resource is nil when Carbide reported the resource as missing
+	resource any
+	expires  time.Time
+}
+
+// preflightCache caches Carbide lookups, including missing resources
+type preflightCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[preflightKey]preflightEntry
+}
+
+func newPreflightCache(ttl time.Duration) *preflightCache {
+	return &preflightCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[preflightKey]preflightEntry{},
+	}
+}
+
+func (c *preflightCache) get(key preflightKey) (any, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.resource, true
+}
+
+func (c *preflightCache) set(key preflightKey, resource any) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = preflightEntry{resource: resource, expires: c.now().Add(c.ttl)}
+}
+
+// lookupReference gets a Carbide resource through the cache. A nil resource
+// with a nil error means that Carbide does not know the resource or does not
+// let the tenant see it.
+func lookupReference[T any](
+	ctx context.Context,
+	cache *preflightCache,
+	kind, orgName string,
+	secretKey client.ObjectKey,
+	id string,
+	get func(ctx context.Context, org string, id string) (*T, *http.Response, error),
+) (*T, error) {
+	key := preflightKey{kind: kind, orgName: orgName, credentialsSecret: secretKey, id: id}
+	if cached, ok := cache.get(key); ok {
+		resource, _ := cached.(*T)
+		return resource, nil
+	}
+
+	resource, httpResp, err := get(ctx, orgName, id)
+	if err != nil {
+		if httpResp != nil &&
+			(httpResp.StatusCode == http.StatusNotFound || httpResp.StatusCode == http.StatusForbidden) {
+			cache.set(key, nil)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s %s: %w", kind, id, err)
+	}
+	if resource == nil {
+		return nil, fmt.Errorf("get %s %s returned no data", kind, id)
+	}
+	cache.set(key, resource)
+	return resource, nil
+}
+
+// checkReferences resolves the site, VPC, subnets, instance type and SSH key
+// groups of the provider spec and returns an InvalidConfigurationError listing
+// every reference that is missing or belongs to another site or tenant.
+// Lookup failures other than rejected credentials are logged and skip the
+// affected check, so that Carbide remains the authority on the create.
+func (a *Actuator) checkReferences(
+	ctx context.Context,
+	nvidiaCarbideClient NvidiaCarbideClientInterface,
+	orgName string,
+	secretKey client.ObjectKey,
+	providerSpec *v1.NvidiaCarbideMachineProviderSpec,
+) error {
+	logger := log.FromContext(ctx)
+	var problems []string
+	// skip reports whether a lookup failed and its checks must be skipped
+	skip := func(err error) (bool, error) {
+		if err == nil {
+			return false, nil
+		}
+		if errors.As(err, new(*CredentialsRejectedError)) {
+			return true, err
+		}
+		logger.Error(err, "failed to resolve reference, skipping its pre-flight check")
+		return true, nil
+	}
+
+	site, err := lookupReference(ctx, a.preflight, "site", orgName, secretKey,
+		providerSpec.SiteID, nvidiaCarbideClient.GetSite)
+	if skipped, err := skip(err); err != nil {
+		return err
+	} else if !skipped && site == nil {
+		problems = append(problems, fmt.Sprintf("site %s not found", providerSpec.SiteID))
+	}
+
+	vpc, err := lookupReference(ctx, a.preflight, "VPC", orgName, secretKey,
+		providerSpec.VpcID, nvidiaCarbideClient.GetVpc)
+	if skipped, err := skip(err); err != nil {
+		return err
+	} else if !skipped {
+		switch {
+		case vpc == nil:
+			problems = append(problems, fmt.Sprintf("VPC %s not found", providerSpec.VpcID))
+		case vpc.SiteId != nil && *vpc.SiteId != providerSpec.SiteID:
+			problems = append(problems, fmt.Sprintf("VPC %s belongs to site %s, not %s",
+				providerSpec.VpcID, *vpc.SiteId, providerSpec.SiteID))
+		case vpc.TenantId != nil && *vpc.TenantId != providerSpec.TenantID:
+			problems = append(problems, fmt.Sprintf("VPC %s belongs to tenant %s, not %s",
+				providerSpec.VpcID, *vpc.TenantId, providerSpec.TenantID))
+		}
+	}
+
+	for i, subnetID := range providerSpec.SubnetIDs() {
+		subnet, err := lookupReference(ctx, a.preflight, "subnet", orgName, secretKey,
+			subnetID, nvidiaCarbideClient.GetSubnet)
+		if skipped, err := skip(err); err != nil {
+			return err
+		} else if skipped {
+			continue
+		}
+		switch {
+		case subnet == nil:
+			problems = append(problems, fmt.Sprintf("subnet %s not found", subnetID))
+		case subnet.SiteId != nil && *subnet.SiteId != providerSpec.SiteID:
+			problems = append(problems, fmt.Sprintf("subnet %s belongs to site %s, not %s",
+				subnetID, *subnet.SiteId, providerSpec.SiteID))
+		case subnet.TenantId != nil && *subnet.TenantId != providerSpec.TenantID:
+			problems = append(problems, fmt.Sprintf("subnet %s belongs to tenant %s, not %s",
+				subnetID, *subnet.TenantId, providerSpec.TenantID))
+		case i == 0 && subnet.VpcId != nil && *subnet.VpcId != providerSpec.VpcID:
+			problems = append(problems, fmt.Sprintf("subnet %s belongs to VPC %s, not %s",
+				subnetID, *subnet.VpcId, providerSpec.VpcID))
+		}
+	}
+
+	if providerSpec.InstanceTypeID != "" {
+		instanceType, err := lookupReference(ctx, a.preflight, "instance type", orgName, secretKey,
+			providerSpec.InstanceTypeID, nvidiaCarbideClient.GetInstanceType)
+		if skipped, err := skip(err); err != nil {
+			return err
+		} else if !skipped {
+			switch {
+			case instanceType == nil:
+				problems = append(problems, fmt.Sprintf("instance type %s not found", providerSpec.InstanceTypeID))
+			case instanceType.SiteId != nil && *instanceType.SiteId != providerSpec.SiteID:
+				problems = append(problems, fmt.Sprintf("instance type %s belongs to site %s, not %s",
+					providerSpec.InstanceTypeID, *instanceType.SiteId, providerSpec.SiteID))
+			}
+		}
+	}
+
+	for _, sshKeyGroupID := range providerSpec.SSHKeyGroupIDs {
+		sshKeyGroup, err := lookupReference(ctx, a.preflight, "SSH key group", orgName, secretKey, sshKeyGroupID,
+			nvidiaCarbideClient.GetSSHKeyGroup)
+		if skipped, err := skip(err); err != nil {
+			return err
+		} else if skipped {
+			continue
+		}
+		switch {
+		case sshKeyGroup == nil:
+			problems = append(problems, fmt.Sprintf("SSH key group %s not found", sshKeyGroupID))
+		case sshKeyGroup.TenantId != nil && *sshKeyGroup.TenantId != providerSpec.TenantID:
+			problems = append(problems, fmt.Sprintf("SSH key group %s belongs to tenant %s, not %s",
+				sshKeyGroupID, *sshKeyGroup.TenantId, providerSpec.TenantID))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &InvalidConfigurationError{Problems: problems}
+	}
+	return nil
+}
+
+// reportInvalidConfiguration records unresolved references on the Machine
+func (a *Actuator) reportInvalidConfiguration(ctx context.Context, machineObj client.Object, err error) {
+	invalidErr := &InvalidConfigurationError{}
+	if !errors.As(err, &invalidErr) {
+		return
+	}
+
+	if a.eventRecorder != nil {
+		a.eventRecorder.Event(machineObj, corev1.EventTypeWarning, v1.InvalidConfigurationReason, invalidErr.Error())
+	}
+	if condErr := a.setCondition(machineObj, metav1.Condition{
+		Type:    v1.ReferencesResolvedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1.InvalidConfigurationReason,
+		Message: invalidErr.Error(),
+	}); condErr != nil {
+		log.FromContext(ctx).Error(condErr, "failed to set references condition",
+			"machine", machineObj.GetName())
+	}
+}
+
+// referencesResolvedCondition is reported once an instance was created
+func referencesResolvedCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:   v1.ReferencesResolvedCondition,
+		Status: metav1.ConditionTrue,
+		Reason: v1.ReferencesResolvedReason,
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/tracing"
+)
+
+// RebootRequestAnnotation requests a power cycle of the instance of a Machine.
+// Each distinct value is handled once.
+const RebootRequestAnnotation = "nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/reboot-request"
+
+// rebootRequest power cycles an instance
+var rebootRequest = instanceRequest{
+	name: "reboot",
+	last: func(status *v1.NvidiaCarbideMachineProviderStatus) **v1.RequestStatus {
+		return &status.LastRebootRequest
+	},
+	call: func(ctx context.Context, nvidiaCarbideClient NvidiaCarbideClientInterface,
+		orgName, instanceID string, _ *v1.NvidiaCarbideMachineProviderSpec) error {
+		_, err := nvidiaCarbideClient.RebootInstance(ctx, orgName, instanceID)
+		return err
+	},
+	succeededReason:  "Rebooted",
+	failedReason:     "RebootFailed",
+	succeededMessage: "Rebooted instance %s",
+}
+
+// Reboot power cycles the instance of a Machine when its reboot request
+// annotation holds a token that was not handled yet
+func (a *Actuator) Reboot(ctx context.Context, machine runtime.Object) error {
+	machineObj, ok := machine.(client.Object)
+	if !ok {
+		return fmt.Errorf("machine is not a client.Object")
+	}
+	token := machineObj.GetAnnotations()[RebootRequestAnnotation]
+	if token == "" {
+		return nil
+	}
+	_, err := a.RequestReboot(ctx, machine, token)
+	return err
+}
+
+// RequestReboot power cycles the instance of a Machine once per token. The
+// outcome is recorded in the lastRebootRequest provider status field and
+// returned.
+func (a *Actuator) RequestReboot(
+	ctx context.Context, machine runtime.Object, token string,
+) (_ *v1.RequestStatus, err error) {
+	ctx, span := tracing.StartSpan(ctx, "Actuator.RequestReboot", machineAttributes(machine)...)
+	defer func() { tracing.EndSpan(span, err) }()
+	machineObj, ok := machine.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("machine is not a client.Object")
+	}
+	return a.runRequest(ctx, machineObj, token, rebootRequest)
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/tracing"
+	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
+)
+
+// ReprovisionRequestAnnotation requests an in-place re-image of the instance
+// of a Machine. Each distinct value is handled once.
+const ReprovisionRequestAnnotation = "nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/reprovision-request"
+
+// bootSourceTokenPrefix prefixes the tokens of re-images triggered by a boot
+// source change
+const bootSourceTokenPrefix = "boot-source-"
+
+// reprovisionRequest re-images an instance in place with the boot source of
+// the provider spec, keeping the same physical machine and instance ID
+var reprovisionRequest = instanceRequest{
+	name: "reprovision",
+	last: func(status *v1.NvidiaCarbideMachineProviderStatus) **v1.RequestStatus {
+		return &status.LastReprovisionRequest
+	},
+	call: func(ctx context.Context, nvidiaCarbideClient NvidiaCarbideClientInterface,
+		orgName, instanceID string, providerSpec *v1.NvidiaCarbideMachineProviderSpec) error {
+		_, _, err := nvidiaCarbideClient.UpdateInstance(ctx, orgName, instanceID, buildReprovisionRequest(providerSpec))
+		return err
+	},
+	succeededReason:  "Reprovisioning",
+	failedReason:     "ReprovisionFailed",
+	succeededMessage: "Re-imaging instance %s",
+	record: func(status *v1.NvidiaCarbideMachineProviderStatus, providerSpec *v1.NvidiaCarbideMachineProviderSpec,
+		instanceID string, callErr error) {
+		// A failed re-image is not retried for the same boot source
+		status.BootSourceHash = bootSourceHash(providerSpec)
+		meta.SetStatusCondition(&status.Conditions, reprovisionStartedCondition(instanceID, callErr))
+	},
+}
+
+// bootSourceHash returns a hash of the boot source of a provider spec
+func bootSourceHash(providerSpec *v1.NvidiaCarbideMachineProviderSpec) string {
+	// Marshalling a struct of strings cannot fail
+	raw, _ := json.Marshal(providerSpec.BootSource)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// reprovisionStartedCondition reports a re-image accepted or rejected by
+// NVIDIA Carbide
+func reprovisionStartedCondition(instanceID string, callErr error) metav1.Condition {
+	if callErr != nil {
+		return metav1.Condition{
+			Type:    v1.ReprovisionedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1.ReprovisionFailedReason,
+			Message: fmt.Sprintf("Failed to re-image instance %s: %v", instanceID, callErr),
+		}
+	}
+	return metav1.Condition{
+		Type:    v1.ReprovisionedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1.ReprovisioningReason,
+		Message: fmt.Sprintf("Re-imaging instance %s", instanceID),
+	}
+}
+
+// reprovisionProgressCondition returns the Reprovisioned condition for the
+// current state of an instance being re-imaged, or nil while it is still in
+// progress. The re-image is over once the instance is ready again after the
+// request, with no pending update.
+func reprovisionProgressCondition(instanceID string, instance *bmm.Instance, since time.Time) *metav1.Condition {
+	if instance.Status == nil || (instance.IsUpdatePending != nil && *instance.IsUpdatePending) {
+		return nil
+	}
+	switch *instance.Status {
+	case bmm.INSTANCESTATUS_ERROR:
+		return &metav1.Condition{
+			Type:    v1.ReprovisionedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1.ReprovisionFailedReason,
+			Message: fmt.Sprintf("Instance %s reported an error while being re-imaged", instanceID),
+		}
+	case bmm.INSTANCESTATUS_READY:
+	default:
+		return nil
+	}
+
+	// The instance can still report Ready right after the request, wait for
+	// a transition to Ready recorded after it when the history is available
+	if len(instance.StatusHistory) > 0 {
+		readyAgain := false
+		for _, detail := range instance.StatusHistory {
+			if detail.Status != nil && *detail.Status == string(bmm.INSTANCESTATUS_READY) &&
+				detail.Created != nil && detail.Created.After(since) {
+				readyAgain = true
+				break
+			}
+		}
+		if !readyAgain {
+			return nil
+		}
+	}
+	return &metav1.Condition{
+		Type:    v1.ReprovisionedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1.ReprovisionedReason,
+		Message: fmt.Sprintf("Re-imaged instance %s", instanceID),
+	}
+}
+
+// updateReprovisionProgress completes the Reprovisioned condition of a
+// Machine whose instance was being re-imaged, and reports whether it changed
+func updateReprovisionProgress(status *v1.NvidiaCarbideMachineProviderStatus, instance *bmm.Instance) bool {
+	current := meta.FindStatusCondition(status.Conditions, v1.ReprovisionedCondition)
+	if current == nil || current.Reason != v1.ReprovisioningReason {
+		return false
+	}
+	if status.InstanceID == nil {
+		return false
+	}
+	// The condition keeps its transition time when a failed re-image is
+	// retried, so measure progress from the last accepted request
+	since := current.LastTransitionTime.Time
+	if status.LastReprovisionRequest != nil {
+		since = status.LastReprovisionRequest.Time.Time
+	}
+	condition := reprovisionProgressCondition(*status.InstanceID, instance, since)
+	if condition == nil {
+		return false
+	}
+	return meta.SetStatusCondition(&status.Conditions, *condition)
+}
+
+// buildReprovisionRequest constructs an update request that reboots the
+// instance into the boot source of the provider spec
+func buildReprovisionRequest(providerSpec *v1.NvidiaCarbideMachineProviderSpec) bmm.InstanceUpdateRequest {
+	createReq := buildInstanceRequest("", providerSpec)
+	req := bmm.InstanceUpdateRequest{
+		TriggerReboot:        *bmm.NewNullableBool(ptr(true)),
+		RebootWithCustomIpxe: *bmm.NewNullableBool(ptr(true)),
+		OperatingSystemId:    createReq.OperatingSystemId,
+		IpxeScript:           createReq.IpxeScript,
+	}
+	if createReq.UserData.IsSet() {
+		req.UserData = createReq.UserData
+	}
+	return req
+}
+
+// Reprovision re-images the instance of a Machine in place when its
+// reprovision request annotation holds a token that was not handled yet, or
+// when the boot source of its provider spec changed since the instance was
+// created or last re-imaged
+func (a *Actuator) Reprovision(ctx context.Context, machine runtime.Object) error {
+	machineObj, ok := machine.(client.Object)
+	if !ok {
+		return fmt.Errorf("machine is not a client.Object")
+	}
+	if token := machineObj.GetAnnotations()[ReprovisionRequestAnnotation]; token != "" {
+		providerStatus, err := a.getProviderStatus(machineObj)
+		if err != nil {
+			return fmt.Errorf("failed to get provider status: %w", err)
+		}
+		if last := providerStatus.LastReprovisionRequest; last == nil || last.Token != token {
+			_, err := a.RequestReprovision(ctx, machine, token)
+			return err
+		}
+	}
+
+	providerSpec, err := a.getProviderSpec(machineObj)
+	if err != nil {
+		return fmt.Errorf("failed to get provider spec: %w", err)
+	}
+	providerStatus, err := a.getProviderStatus(machineObj)
+	if err != nil {
+		return fmt.Errorf("failed to get provider status: %w", err)
+	}
+	if providerStatus.InstanceID == nil {
+		return nil
+	}
+	hash := bootSourceHash(providerSpec)
+	switch providerStatus.BootSourceHash {
+	case hash:
+		return nil
+	case "":
+		// Instances created before the hash was recorded are assumed to run
+		// their current boot source
+		providerStatus.BootSourceHash = hash
+		if err := a.setProviderStatus(machineObj, providerStatus); err != nil {
+			return fmt.Errorf("failed to record boot source hash: %w", err)
+		}
+		return nil
+	}
+	_, err = a.RequestReprovision(ctx, machine, bootSourceTokenPrefix+hash)
+	return err
+}
+
+// RequestReprovision re-images the instance of a Machine in place once per
+// token. The outcome is recorded in the lastReprovisionRequest provider status
+// field and returned.
+func (a *Actuator) RequestReprovision(
+	ctx context.Context, machine runtime.Object, token string,
+) (_ *v1.RequestStatus, err error) {
+	ctx, span := tracing.StartSpan(ctx, "Actuator.RequestReprovision", machineAttributes(machine)...)
+	defer func() { tracing.EndSpan(span, err) }()
+	machineObj, ok := machine.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("machine is not a client.Object")
+	}
+	return a.runRequest(ctx, machineObj, token, reprovisionRequest)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+)
+
+// instanceRequest is an operation run once per token on the instance of a Machine
+type instanceRequest struct {
+	// name is used in logs and event messages, e.g. "reboot"
+	name string
+	// last returns the provider status field recording the last request
+	last func(status *v1.NvidiaCarbideMachineProviderStatus) **v1.RequestStatus
+	// call runs the operation
+	call func(ctx context.Context, nvidiaCarbideClient NvidiaCarbideClientInterface,
+		orgName, instanceID string, providerSpec *v1.NvidiaCarbideMachineProviderSpec) error
+	// succeededReason and failedReason are the event reasons of the outcome
+	succeededReason string
+	failedReason    string
+	// succeededMessage is the event message of a success, formatted with the instance ID
+	succeededMessage string
+	// record, when set, updates the provider status with the outcome of the call
+	record func(status *v1.NvidiaCarbideMachineProviderStatus, providerSpec *v1.NvidiaCarbideMachineProviderSpec,
+		instanceID string, callErr error)
+}
+
+// runRequest runs req on the instance of a Machine unless token was already
+// handled. The token is recorded in the provider status before Carbide is
+// called, so that a request is never repeated, even when the result cannot be
+// recorded. A request rejected by Carbide is recorded as failed and not
+// retried. The recorded request is returned, or nil while the instance does
+// not exist.
+func (a *Actuator) runRequest(
+	ctx context.Context, machineObj client.Object, token string, req instanceRequest,
+) (*v1.RequestStatus, error) {
+	providerStatus, err := a.getProviderStatus(machineObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider status: %w", err)
+	}
+	if last := *req.last(providerStatus); last != nil && last.Token == token {
+		return last, nil
+	}
+	if providerStatus.InstanceID == nil {
+		// Handled once the instance exists
+		return nil, nil
+	}
+	instanceID := *providerStatus.InstanceID
+
+	providerSpec, err := a.getProviderSpec(machineObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider spec: %w", err)
+	}
+	nvidiaCarbideClient, orgName, err := a.getNvidiaCarbideClient(ctx, machineObj, providerSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NVIDIA Carbide client: %w", err)
+	}
+
+	last := req.last(providerStatus)
+	*last = &v1.RequestStatus{
+		Token:  token,
+		Result: v1.RequestPending,
+		Time:   metav1.Now(),
+	}
+	if err := a.setProviderStatus(machineObj, providerStatus); err != nil {
+		return nil, fmt.Errorf("failed to record %s request: %w", req.name, err)
+	}
+
+	logger := log.FromContext(ctx).WithValues("instanceID", instanceID, "token", token)
+	callErr := req.call(ctx, nvidiaCarbideClient, orgName, instanceID, providerSpec)
+	(*last).Time = metav1.Now()
+	if callErr != nil {
+		(*last).Result = v1.RequestFailed
+		(*last).Message = callErr.Error()
+	} else {
+		(*last).Result = v1.RequestSucceeded
+	}
+	if req.record != nil {
+		req.record(providerStatus, providerSpec, instanceID, callErr)
+	}
+	if err := a.setProviderStatus(machineObj, providerStatus); err != nil {
+		return nil, fmt.Errorf("failed to record %s result: %w", req.name, err)
+	}
+
+	if callErr != nil {
+		logger.Error(callErr, "failed to "+req.name+" instance")
+		a.reportCredentialsRejected(ctx, machineObj, callErr)
+		if a.eventRecorder != nil {
+			a.eventRecorder.Eventf(machineObj, corev1.EventTypeWarning, req.failedReason,
+				"Failed to %s instance %s: %v", req.name, instanceID, callErr)
+		}
+		return *last, nil
+	}
+
+	logger.Info("Requested instance " + req.name)
+	if a.eventRecorder != nil {
+		a.eventRecorder.Eventf(machineObj, corev1.EventTypeNormal, req.succeededReason, req.succeededMessage, instanceID)
+	}
+	return *last, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	kjson "sigs.k8s.io/json"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	v1beta1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1beta1"
+)
+
+// providerSpecFieldErrors returns the unknown and duplicate fields of a raw
+// provider spec. Field names are matched case-sensitively, as the API server
+// does, so that subnetID is reported instead of being taken for subnetId.
+// Syntax errors and unsupported versions are left to the regular decoding.
+func providerSpecFieldErrors(providerSpecBytes []byte) []string {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(providerSpecBytes, &typeMeta); err != nil {
+		return nil
+	}
+	var providerSpec any = &v1beta1.NvidiaCarbideMachineProviderSpec{}
+	if typeMeta.APIVersion == v1.SchemeGroupVersion.String() {
+		providerSpec = &v1.NvidiaCarbideMachineProviderSpec{}
+	}
+
+	strictErrs, err := kjson.UnmarshalStrict(providerSpecBytes, providerSpec)
+	if err != nil {
+		return nil
+	}
+	var problems []string
+	for _, strictErr := range strictErrs {
+		problems = append(problems, strictErr.Error())
+	}
+	return problems
+}
+
+// providerSpecFieldProblems returns the unknown and duplicate fields of the
+// Machine provider spec. Duplicates cannot be seen on unstructured Machines,
+// where the JSON was already decoded into a map.
+func providerSpecFieldProblems(machineObj client.Object) ([]string, error) {
+	providerSpecBytes, err := getProviderSpecBytes(machineObj)
+	if err != nil {
+		return nil, err
+	}
+	return providerSpecFieldErrors(providerSpecBytes), nil
+}
+
+// checkProviderSpecFields reports unknown and duplicate provider spec fields
+// on the Machine and, in strict mode, returns them as an
+// InvalidConfigurationError
+func (a *Actuator) checkProviderSpecFields(ctx context.Context, machineObj client.Object) ([]string, error) {
+	problems, err := providerSpecFieldProblems(machineObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider spec: %w", err)
+	}
+	if len(problems) == 0 {
+		return nil, nil
+	}
+
+	message := unknownFieldsMessage(problems)
+	if a.eventRecorder != nil {
+		a.eventRecorder.Event(machineObj, corev1.EventTypeWarning, v1.UnknownFieldsReason, message)
+	}
+	if condErr := a.setCondition(machineObj, providerSpecFieldsCondition(problems)); condErr != nil {
+		log.FromContext(ctx).Error(condErr, "failed to set provider spec fields condition",
+			"machine", machineObj.GetName())
+	}
+
+	if a.strictProviderSpec {
+		return problems, &InvalidConfigurationError{Problems: problems}
+	}
+	return problems, nil
+}
+
+func unknownFieldsMessage(problems []string) string {
+	return "unknown or duplicate provider spec fields: " + strings.Join(problems, "; ")
+}
+
+// providerSpecFieldsCondition reports the unknown and duplicate provider spec
+// fields, if any
+func providerSpecFieldsCondition(problems []string) metav1.Condition {
+	if len(problems) == 0 {
+		return metav1.Condition{
+			Type:   v1.ProviderSpecFieldsValidCondition,
+			Status: metav1.ConditionTrue,
+			Reason: v1.ProviderSpecFieldsValidReason,
+		}
+	}
+	return metav1.Condition{
+		Type:    v1.ProviderSpecFieldsValidCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1.UnknownFieldsReason,
+		Message: unknownFieldsMessage(problems),
+	}
+}
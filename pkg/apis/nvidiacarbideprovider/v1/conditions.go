@@ -0,0 +1,99 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// Condition types reported in NvidiaCarbideMachineProviderStatus.Conditions
+const (
+	// CredentialsValidCondition reports whether the credentials Secret can be used
+	CredentialsValidCondition = "CredentialsValid"
+
+	// CapacityAvailableCondition reports whether the site had a free host of the
+	// instance type when the instance was last created
+	CapacityAvailableCondition = "CapacityAvailable"
+
+	// ProviderSpecAppliedCondition reports whether the provider spec matches the
+	// provisioned instance
+	ProviderSpecAppliedCondition = "ProviderSpecApplied"
+
+	// ReferencesResolvedCondition reports whether the NVIDIA Carbide resources
+	// referenced by the provider spec exist and belong to its site and tenant
+	ReferencesResolvedCondition = "ReferencesResolved"
+
+	// ProviderSpecFieldsValidCondition reports whether the provider spec only
+	// has known fields, each set once
+	ProviderSpecFieldsValidCondition = "ProviderSpecFieldsValid"
+
+	// ReprovisionedCondition reports the progress of the last in-place re-image
+	// of the instance
+	ReprovisionedCondition = "Reprovisioned"
+)
+
+// Condition reasons
+const (
+	// CredentialsAcceptedReason is set once NVIDIA Carbide accepted the credentials
+	CredentialsAcceptedReason = "CredentialsAccepted"
+
+	// CredentialsRejectedReason is set when NVIDIA Carbide rejects the token even
+	// after the credentials Secret was re-read
+	CredentialsRejectedReason = "CredentialsRejected"
+
+	// CredentialsSecretNotAllowedReason is set when the credentials Secret lives
+	// outside the namespaces the provider may read from
+	CredentialsSecretNotAllowedReason = "CredentialsSecretNotAllowed"
+
+	// CredentialsSecretNotSpecifiedReason is set when neither the provider spec nor
+	// the manager configure a credentials Secret
+	CredentialsSecretNotSpecifiedReason = "CredentialsSecretNotSpecified"
+
+	// CapacityAvailableReason is set when a free host of the instance type was found
+	CapacityAvailableReason = "CapacityAvailable"
+
+	// InsufficientCapacityReason is set when the site has no free host of the
+	// instance type
+	InsufficientCapacityReason = "InsufficientCapacity"
+
+	// ProviderSpecAppliedReason is set when the provider spec matches the instance
+	ProviderSpecAppliedReason = "ProviderSpecApplied"
+
+	// ImmutableFieldChangedReason is set when a field that cannot be applied to
+	// a provisioned instance was changed
+	ImmutableFieldChangedReason = "ImmutableFieldChanged"
+
+	// ReferencesResolvedReason is set when all referenced resources were found
+	ReferencesResolvedReason = "ReferencesResolved"
+
+	// InvalidConfigurationReason is set when a referenced resource is missing or
+	// belongs to another site or tenant
+	InvalidConfigurationReason = "InvalidConfiguration"
+
+	// ProviderSpecFieldsValidReason is set when all provider spec fields are known
+	ProviderSpecFieldsValidReason = "ProviderSpecFieldsValid"
+
+	// UnknownFieldsReason is set when the provider spec has unknown or
+	// duplicate fields
+	UnknownFieldsReason = "UnknownFields"
+
+	// ReprovisioningReason is set while the instance is being re-imaged
+	ReprovisioningReason = "Reprovisioning"
+
+	// ReprovisionedReason is set once the re-imaged instance is ready again
+	ReprovisionedReason = "Reprovisioned"
+
+	// ReprovisionFailedReason is set when NVIDIA Carbide rejected the re-image
+	// or the instance reported an error while being re-imaged
+	ReprovisionFailedReason = "ReprovisionFailed"
+)
@@ -0,0 +1,53 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{
+		Group:   "nvidiacarbideprovider.infrastructure.cluster.x-k8s.io",
+		Version: "v1",
+	}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+
+	// AddToScheme adds the types in this group-version to the given scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	// NvidiaCarbideMachineProviderSpec and NvidiaCarbideMachineProviderStatus
+	// are embedded in Machines via providerSpec.value and providerStatus rather
+	// than served as CRDs, they are registered so that they can be decoded
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&NvidiaCarbideMachineProviderSpec{},
+		&NvidiaCarbideMachineProviderStatus{},
+		&NvidiaCarbideRemediation{},
+		&NvidiaCarbideRemediationList{},
+		&NvidiaCarbideRemediationTemplate{},
+		&NvidiaCarbideRemediationTemplateList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NvidiaCarbideRemediationPhase is the current remediation step
+type NvidiaCarbideRemediationPhase string
+
+const (
+	// RemediationPhasePowerCycling is set while waiting for the Machine to
+	// recover from a power cycle of its instance
+	RemediationPhasePowerCycling NvidiaCarbideRemediationPhase = "PowerCycling"
+
+	// RemediationPhaseReprovisioning is set while waiting for the Machine to
+	// recover from an in-place re-image of its instance
+	RemediationPhaseReprovisioning NvidiaCarbideRemediationPhase = "Reprovisioning"
+
+	// RemediationPhaseDeleting is set once the Machine was deleted so that its
+	// MachineSet replaces it
+	RemediationPhaseDeleting NvidiaCarbideRemediationPhase = "Deleting"
+)
+
+// NvidiaCarbideRemediationSpec configures the remediation steps of an
+// unhealthy Machine. Each step lasts until its timeout, after which the next
+// step runs. A zero timeout skips the step.
+// +kubebuilder:object:generate=true
+type NvidiaCarbideRemediationSpec struct {
+	// PowerCycleTimeout is how long to wait for the Machine to recover after
+	// its instance is power cycled. Defaults to 10m.
+	// +optional
+	PowerCycleTimeout *metav1.Duration `json:"powerCycleTimeout,omitempty"`
+
+	// ReprovisionTimeout is how long to wait for the Machine to recover after
+	// its instance is re-imaged in place. Defaults to 60m.
+	// +optional
+	ReprovisionTimeout *metav1.Duration `json:"reprovisionTimeout,omitempty"`
+}
+
+// NvidiaCarbideRemediationStatus reports the remediation progress
+// +kubebuilder:object:generate=true
+type NvidiaCarbideRemediationStatus struct {
+	// Phase is the current remediation step
+	// +kubebuilder:validation:Enum=PowerCycling;Reprovisioning;Deleting
+	// +optional
+	Phase NvidiaCarbideRemediationPhase `json:"phase,omitempty"`
+
+	// StepStartTime is when the current step started
+	// +optional
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+
+	// Message describes the current step
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// NvidiaCarbideRemediation remediates an unhealthy Machine of the same name. It
+// is created and deleted by a MachineHealthCheck using a
+// NvidiaCarbideRemediationTemplate as external remediation template.
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type NvidiaCarbideRemediation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NvidiaCarbideRemediationSpec   `json:"spec,omitempty"`
+	Status NvidiaCarbideRemediationStatus `json:"status,omitempty"`
+}
+
+// NvidiaCarbideRemediationList contains a list of NvidiaCarbideRemediation
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+type NvidiaCarbideRemediationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NvidiaCarbideRemediation `json:"items"`
+}
+
+// NvidiaCarbideRemediationTemplateResource is the remediation created from a template
+// +kubebuilder:object:generate=true
+type NvidiaCarbideRemediationTemplateResource struct {
+	// Spec is the spec of the created NvidiaCarbideRemediation
+	Spec NvidiaCarbideRemediationSpec `json:"spec"`
+}
+
+// NvidiaCarbideRemediationTemplateSpec defines the remediations created from the template
+// +kubebuilder:object:generate=true
+type NvidiaCarbideRemediationTemplateSpec struct {
+	Template NvidiaCarbideRemediationTemplateResource `json:"template"`
+}
+
+// NvidiaCarbideRemediationTemplate is referenced by the remediationTemplate of
+// a MachineHealthCheck
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+type NvidiaCarbideRemediationTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NvidiaCarbideRemediationTemplateSpec `json:"spec,omitempty"`
+}
+
+// NvidiaCarbideRemediationTemplateList contains a list of NvidiaCarbideRemediationTemplate
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+type NvidiaCarbideRemediationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NvidiaCarbideRemediationTemplate `json:"items"`
+}
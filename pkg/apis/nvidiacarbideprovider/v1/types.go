@@ -0,0 +1,232 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NvidiaCarbideMachineProviderSpec defines the desired state for OpenShift Machine API
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+type NvidiaCarbideMachineProviderSpec struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// SiteID is the NVIDIA Carbide Site UUID
+	// +required
+	SiteID string `json:"siteId"`
+
+	// TenantID is the NVIDIA Carbide tenant ID
+	// +required
+	TenantID string `json:"tenantId"`
+
+	// InstanceTypeID specifies the NVIDIA Carbide instance type UUID
+	// Mutually exclusive with MachineID
+	// +optional
+	InstanceTypeID string `json:"instanceTypeId,omitempty"`
+
+	// MachineID specifies a specific machine UUID for targeted provisioning
+	// Mutually exclusive with InstanceTypeID
+	// +optional
+	MachineID string `json:"machineId,omitempty"`
+
+	// AllowUnhealthyMachine allows provisioning on an unhealthy machine
+	// +optional
+	AllowUnhealthyMachine bool `json:"allowUnhealthyMachine,omitempty"`
+
+	// VpcID is the VPC UUID
+	// +required
+	VpcID string `json:"vpcId"`
+
+	// Interfaces are the network interfaces of the instance, the first one is
+	// the primary interface
+	// +required
+	// +listType=atomic
+	Interfaces []NetworkInterface `json:"interfaces"`
+
+	// BootSource selects what the instance boots and the user data it gets
+	// +optional
+	BootSource BootSource `json:"bootSource,omitempty"`
+
+	// SSHKeyGroupIDs contains SSH key group IDs
+	// +optional
+	// +listType=atomic
+	SSHKeyGroupIDs []string `json:"sshKeyGroupIds,omitempty"`
+
+	// Labels to apply to the NVIDIA Carbide instance
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// DeletionProtection keeps the instance when the Machine is deleted, until
+	// the flag is removed
+	// +optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
+	// CredentialsSecret references a secret containing NVIDIA Carbide API credentials
+	// The secret must contain: endpoint, orgName, token
+	// When unset, the manager default credentials secret is used. When the
+	// namespace is unset, the Machine namespace is used.
+	// +optional
+	CredentialsSecret *corev1.SecretReference `json:"credentialsSecret,omitempty"`
+}
+
+// NetworkInterface attaches the instance to a subnet
+// +kubebuilder:object:generate=true
+// +k8s:openapi-gen=true
+type NetworkInterface struct {
+	// SubnetID is the subnet UUID for this interface
+	// +required
+	SubnetID string `json:"subnetId"`
+
+	// IsPhysical indicates if this is a physical interface
+	// +optional
+	IsPhysical bool `json:"isPhysical,omitempty"`
+}
+
+// BootSource defines how the instance is booted. At most one of
+// OperatingSystemID and IPXEScript may be set; when neither is, a minimal iPXE
+// script is used.
+// +kubebuilder:object:generate=true
+// +k8s:openapi-gen=true
+type BootSource struct {
+	// OperatingSystemID is the NVIDIA Carbide operating system UUID
+	// +optional
+	OperatingSystemID string `json:"operatingSystemId,omitempty"`
+
+	// IPXEScript is a custom iPXE script
+	// +optional
+	IPXEScript string `json:"ipxeScript,omitempty"`
+
+	// UserData contains the cloud-init user data
+	// +optional
+	UserData string `json:"userData,omitempty"`
+}
+
+// PrimarySubnetID returns the subnet of the primary interface
+func (s *NvidiaCarbideMachineProviderSpec) PrimarySubnetID() string {
+	if len(s.Interfaces) == 0 {
+		return ""
+	}
+	return s.Interfaces[0].SubnetID
+}
+
+// SubnetIDs returns the subnets of all interfaces, primary first
+func (s *NvidiaCarbideMachineProviderSpec) SubnetIDs() []string {
+	subnetIDs := make([]string, 0, len(s.Interfaces))
+	for _, iface := range s.Interfaces {
+		subnetIDs = append(subnetIDs, iface.SubnetID)
+	}
+	return subnetIDs
+}
+
+// NvidiaCarbideMachineProviderStatus defines the observed state for OpenShift Machine API
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+type NvidiaCarbideMachineProviderStatus struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// InstanceID is the NVIDIA Carbide instance ID
+	// +optional
+	InstanceID *string `json:"instanceId,omitempty"`
+
+	// MachineID is the physical machine ID
+	// +optional
+	MachineID *string `json:"machineId,omitempty"`
+
+	// InstanceState represents the current state of the instance
+	// +optional
+	InstanceState *string `json:"instanceState,omitempty"`
+
+	// ProvisionedTime is when the instance was first seen Ready
+	// +optional
+	ProvisionedTime *metav1.Time `json:"provisionedTime,omitempty"`
+
+	// Addresses contains the IP addresses assigned to the machine
+	// +optional
+	// +listType=atomic
+	Addresses []MachineAddress `json:"addresses,omitempty"`
+
+	// Conditions represent the current state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastRebootRequest is the last reboot request handled for the Machine
+	// +optional
+	LastRebootRequest *RequestStatus `json:"lastRebootRequest,omitempty"`
+
+	// LastReprovisionRequest is the last reprovision request handled for the Machine
+	// +optional
+	LastReprovisionRequest *RequestStatus `json:"lastReprovisionRequest,omitempty"`
+
+	// BootSourceHash is the hash of the boot source last sent to NVIDIA Carbide
+	// when the instance was created or re-imaged
+	// +optional
+	BootSourceHash string `json:"bootSourceHash,omitempty"`
+}
+
+// RequestResult is the outcome of a reboot or reprovision request
+type RequestResult string
+
+const (
+	// RequestPending is recorded before Carbide is called, so a request
+	// interrupted midway is not repeated
+	RequestPending RequestResult = "Pending"
+	// RequestSucceeded is recorded once Carbide accepted the request
+	RequestSucceeded RequestResult = "Succeeded"
+	// RequestFailed is recorded when Carbide rejected the request
+	RequestFailed RequestResult = "Failed"
+)
+
+// RequestStatus records a handled reboot or reprovision request
+// +kubebuilder:object:generate=true
+// +k8s:openapi-gen=true
+type RequestStatus struct {
+	// Token identifies the request, each token is handled once
+	// +required
+	Token string `json:"token"`
+
+	// Result is the outcome of the request
+	// +required
+	Result RequestResult `json:"result"`
+
+	// Message describes a failure
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Time is when the request was handled
+	// +required
+	Time metav1.Time `json:"time"`
+}
+
+// MachineAddress contains information for a machine's network address
+// +kubebuilder:object:generate=true
+// +k8s:openapi-gen=true
+type MachineAddress struct {
+	// Type of the address (e.g., InternalIP, ExternalIP)
+	// +required
+	Type string `json:"type"`
+
+	// Address is the IP address
+	// +required
+	Address string `json:"address"`
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+)
+
+// ConvertTo converts the provider spec to v1. The primary subnet becomes the
+// first interface and the user data moves to the boot source.
+func (src *NvidiaCarbideMachineProviderSpec) ConvertTo(dst *v1.NvidiaCarbideMachineProviderSpec) {
+	dst.TypeMeta = src.TypeMeta
+	dst.APIVersion = v1.SchemeGroupVersion.String()
+	dst.ObjectMeta = src.ObjectMeta
+	dst.SiteID = src.SiteID
+	dst.TenantID = src.TenantID
+	dst.InstanceTypeID = src.InstanceTypeID
+	dst.MachineID = src.MachineID
+	dst.AllowUnhealthyMachine = src.AllowUnhealthyMachine
+	dst.VpcID = src.VpcID
+
+	dst.Interfaces = make([]v1.NetworkInterface, 0, 1+len(src.AdditionalSubnetIDs))
+	dst.Interfaces = append(dst.Interfaces, v1.NetworkInterface{SubnetID: src.SubnetID})
+	for _, subnet := range src.AdditionalSubnetIDs {
+		dst.Interfaces = append(dst.Interfaces, v1.NetworkInterface{
+			SubnetID:   subnet.SubnetID,
+			IsPhysical: subnet.IsPhysical,
+		})
+	}
+
+	dst.BootSource = v1.BootSource{UserData: src.UserData}
+	dst.SSHKeyGroupIDs = src.SSHKeyGroupIDs
+	dst.Labels = src.Labels
+	dst.DeletionProtection = src.DeletionProtection
+
+	dst.CredentialsSecret = nil
+	if src.CredentialsSecret.Name != "" || src.CredentialsSecret.Namespace != "" {
+		dst.CredentialsSecret = &corev1.SecretReference{
+			Name:      src.CredentialsSecret.Name,
+			Namespace: src.CredentialsSecret.Namespace,
+		}
+	}
+}
+
+// ConvertFrom converts a v1 provider spec back to v1beta1. The first interface
+// becomes the primary subnet and the user data is taken from the boot source.
+// The operating system, iPXE script and IsPhysical of the primary interface
+// have no v1beta1 equivalent and are dropped.
+func (dst *NvidiaCarbideMachineProviderSpec) ConvertFrom(src *v1.NvidiaCarbideMachineProviderSpec) {
+	dst.TypeMeta = src.TypeMeta
+	dst.APIVersion = SchemeGroupVersion.String()
+	dst.ObjectMeta = src.ObjectMeta
+	dst.SiteID = src.SiteID
+	dst.TenantID = src.TenantID
+	dst.InstanceTypeID = src.InstanceTypeID
+	dst.MachineID = src.MachineID
+	dst.AllowUnhealthyMachine = src.AllowUnhealthyMachine
+	dst.VpcID = src.VpcID
+
+	dst.SubnetID = src.PrimarySubnetID()
+	dst.AdditionalSubnetIDs = nil
+	if len(src.Interfaces) > 1 {
+		dst.AdditionalSubnetIDs = make([]AdditionalSubnet, 0, len(src.Interfaces)-1)
+		for _, iface := range src.Interfaces[1:] {
+			dst.AdditionalSubnetIDs = append(dst.AdditionalSubnetIDs, AdditionalSubnet{
+				SubnetID:   iface.SubnetID,
+				IsPhysical: iface.IsPhysical,
+			})
+		}
+	}
+
+	dst.UserData = src.BootSource.UserData
+	dst.SSHKeyGroupIDs = src.SSHKeyGroupIDs
+	dst.Labels = src.Labels
+	dst.DeletionProtection = src.DeletionProtection
+
+	dst.CredentialsSecret = CredentialsSecretReference{}
+	if src.CredentialsSecret != nil {
+		dst.CredentialsSecret = CredentialsSecretReference{
+			Name:      src.CredentialsSecret.Name,
+			Namespace: src.CredentialsSecret.Namespace,
+		}
+	}
+}
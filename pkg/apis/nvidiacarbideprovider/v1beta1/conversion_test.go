@@ -0,0 +1,160 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+)
+
+func newTestProviderSpec() *NvidiaCarbideMachineProviderSpec {
+	return &NvidiaCarbideMachineProviderSpec{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       "NvidiaCarbideMachineProviderSpec",
+		},
+		SiteID:                "site",
+		TenantID:              "tenant",
+		InstanceTypeID:        "instance-type",
+		AllowUnhealthyMachine: true,
+		VpcID:                 "vpc",
+		SubnetID:              "subnet-0",
+		AdditionalSubnetIDs: []AdditionalSubnet{
+			{SubnetID: "subnet-1", IsPhysical: true},
+			{SubnetID: "subnet-2"},
+		},
+		UserData:           "#cloud-config",
+		SSHKeyGroupIDs:     []string{"keys"},
+		Labels:             map[string]string{"team": "ml"},
+		DeletionProtection: true,
+		CredentialsSecret:  CredentialsSecretReference{Name: "creds", Namespace: "openshift-machine-api"},
+	}
+}
+
+func TestConvertTo(t *testing.T) {
+	dst := &v1.NvidiaCarbideMachineProviderSpec{}
+	newTestProviderSpec().ConvertTo(dst)
+
+	want := &v1.NvidiaCarbideMachineProviderSpec{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       "NvidiaCarbideMachineProviderSpec",
+		},
+		SiteID:                "site",
+		TenantID:              "tenant",
+		InstanceTypeID:        "instance-type",
+		AllowUnhealthyMachine: true,
+		VpcID:                 "vpc",
+		Interfaces: []v1.NetworkInterface{
+			{SubnetID: "subnet-0"},
+			{SubnetID: "subnet-1", IsPhysical: true},
+			{SubnetID: "subnet-2"},
+		},
+		BootSource:         v1.BootSource{UserData: "#cloud-config"},
+		SSHKeyGroupIDs:     []string{"keys"},
+		Labels:             map[string]string{"team": "ml"},
+		DeletionProtection: true,
+		CredentialsSecret:  &corev1.SecretReference{Name: "creds", Namespace: "openshift-machine-api"},
+	}
+	if !equality.Semantic.DeepEqual(dst, want) {
+		t.Errorf("ConvertTo() = %+v, want %+v", dst, want)
+	}
+}
+
+func TestConvertTo_WithoutCredentialsSecret(t *testing.T) {
+	src := &NvidiaCarbideMachineProviderSpec{SubnetID: "subnet-0"}
+	dst := &v1.NvidiaCarbideMachineProviderSpec{CredentialsSecret: &corev1.SecretReference{Name: "stale"}}
+	src.ConvertTo(dst)
+
+	if dst.CredentialsSecret != nil {
+		t.Errorf("expected no credentials secret, got %+v", dst.CredentialsSecret)
+	}
+	if dst.APIVersion != v1.SchemeGroupVersion.String() {
+		t.Errorf("apiVersion = %q, want %q", dst.APIVersion, v1.SchemeGroupVersion.String())
+	}
+	if len(dst.Interfaces) != 1 || dst.Interfaces[0].SubnetID != "subnet-0" {
+		t.Errorf("interfaces = %+v, want the primary subnet only", dst.Interfaces)
+	}
+}
+
+func TestConvertFrom_DropsV1OnlyFields(t *testing.T) {
+	src := &v1.NvidiaCarbideMachineProviderSpec{
+		Interfaces: []v1.NetworkInterface{{SubnetID: "subnet-0", IsPhysical: true}},
+		BootSource: v1.BootSource{OperatingSystemID: "os", UserData: "#cloud-config"},
+	}
+	dst := &NvidiaCarbideMachineProviderSpec{}
+	dst.ConvertFrom(src)
+
+	if dst.SubnetID != "subnet-0" || dst.AdditionalSubnetIDs != nil {
+		t.Errorf("subnets = %q and %+v, want subnet-0 only", dst.SubnetID, dst.AdditionalSubnetIDs)
+	}
+	if dst.UserData != "#cloud-config" {
+		t.Errorf("userData = %q, want #cloud-config", dst.UserData)
+	}
+	if dst.CredentialsSecret != (CredentialsSecretReference{}) {
+		t.Errorf("expected no credentials secret, got %+v", dst.CredentialsSecret)
+	}
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *NvidiaCarbideMachineProviderSpec
+	}{
+		{
+			name: "all fields",
+			spec: newTestProviderSpec(),
+		},
+		{
+			name: "minimal",
+			spec: &NvidiaCarbideMachineProviderSpec{
+				TypeMeta: metav1.TypeMeta{APIVersion: SchemeGroupVersion.String()},
+				SiteID:   "site",
+				SubnetID: "subnet-0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &v1.NvidiaCarbideMachineProviderSpec{}
+			tt.spec.ConvertTo(hub)
+
+			// The v1 spec is stored as JSON in the Machine
+			raw, err := json.Marshal(hub)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded := &v1.NvidiaCarbideMachineProviderSpec{}
+			if err := json.Unmarshal(raw, decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			got := &NvidiaCarbideMachineProviderSpec{}
+			got.ConvertFrom(decoded)
+			if !equality.Semantic.DeepEqual(got, tt.spec) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.spec)
+			}
+		})
+	}
+}
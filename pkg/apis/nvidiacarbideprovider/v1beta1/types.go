@@ -20,7 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// NvidiaCarbideMachineProviderSpec defines the desired state for OpenShift Machine API
+// NvidiaCarbideMachineProviderSpec defines the desired state for OpenShift Machine API.
+// It is converted to the v1 provider spec when read.
 type NvidiaCarbideMachineProviderSpec struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -71,10 +72,17 @@ type NvidiaCarbideMachineProviderSpec struct {
 	// +optional
 	Labels map[string]string `json:"labels,omitempty"`
 
+	// DeletionProtection keeps the instance when the Machine is deleted, until
+	// the flag is removed
+	// +optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
 	// CredentialsSecret references a secret containing NVIDIA Carbide API credentials
 	// The secret must contain: endpoint, orgName, token
-	// +required
-	CredentialsSecret CredentialsSecretReference `json:"credentialsSecret"`
+	// When unset, the manager default credentials secret is used. When the
+	// namespace is unset, the Machine namespace is used.
+	// +optional
+	CredentialsSecret CredentialsSecretReference `json:"credentialsSecret,omitempty"`
 }
 
 // AdditionalSubnet defines an additional network interface
@@ -95,8 +103,8 @@ type CredentialsSecretReference struct {
 	Name string `json:"name"`
 
 	// Namespace of the secret
-	// +required
-	Namespace string `json:"namespace"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // NvidiaCarbideMachineProviderStatus defines the observed state for OpenShift Machine API
@@ -0,0 +1,151 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+)
+
+// ProviderName is the name the cloud provider is registered under, passed
+// to the cloud-controller-manager with --cloud-provider
+const ProviderName = "nvidia-carbide"
+
+// Config is the cloud config file passed with --cloud-config
+type Config struct {
+	// CredentialsSecret is the namespace/name of the credentials Secret used
+	// to look up the instances of Nodes
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// Cloud is the NVIDIA Carbide cloud provider. Only InstancesV2 is supported.
+type Cloud struct {
+	secretKey client.ObjectKey
+	reader    client.Reader
+
+	mu            sync.Mutex
+	carbideClient machine.NvidiaCarbideClientInterface
+}
+
+var _ cloudprovider.Interface = &Cloud{}
+
+func init() {
+	cloudprovider.RegisterCloudProvider(ProviderName, func(config io.Reader) (cloudprovider.Interface, error) {
+		return NewCloud(config)
+	})
+}
+
+// NewCloud returns the cloud provider configured by the cloud config file
+func NewCloud(config io.Reader) (*Cloud, error) {
+	if config == nil {
+		return nil, fmt.Errorf("cloud provider %s requires a cloud config file", ProviderName)
+	}
+	data, err := io.ReadAll(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cloud config: %w", err)
+	}
+	cfg := &Config{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse cloud config: %w", err)
+	}
+	secretKey, err := machine.ParseCredentialsSecretKey(cfg.CredentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &Cloud{secretKey: secretKey}, nil
+}
+
+// Initialize creates the client reading the credentials Secret. The Secret is
+// read directly so that only that Secret needs to be readable.
+func (c *Cloud) Initialize(clientBuilder cloudprovider.ControllerClientBuilder, stop <-chan struct{}) {
+	reader, err := client.New(clientBuilder.ConfigOrDie("nvidia-carbide-cloud-provider"), client.Options{})
+	if err != nil {
+		klog.Fatalf("Failed to create client for cloud provider %s: %v", ProviderName, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reader = reader
+}
+
+// getCarbideClient returns the NVIDIA Carbide client, created on first use so
+// that a missing credentials Secret is retried by the cloud controllers
+func (c *Cloud) getCarbideClient(ctx context.Context) (machine.NvidiaCarbideClientInterface, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.carbideClient != nil {
+		return c.carbideClient, nil
+	}
+	if c.reader == nil {
+		return nil, fmt.Errorf("cloud provider %s is not initialized", ProviderName)
+	}
+	carbideClient, _, err := machine.NewNvidiaCarbideClientForSecret(ctx, c.reader, c.secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NVIDIA Carbide client: %w", err)
+	}
+	c.carbideClient = carbideClient
+	return carbideClient, nil
+}
+
+// InstancesV2 returns the Instances resolving Nodes to Carbide instances
+func (c *Cloud) InstancesV2() (cloudprovider.InstancesV2, bool) {
+	return &Instances{getClient: c.getCarbideClient}, true
+}
+
+// LoadBalancer is not supported
+func (c *Cloud) LoadBalancer() (cloudprovider.LoadBalancer, bool) {
+	return nil, false
+}
+
+// Instances is not supported, InstancesV2 is used instead
+func (c *Cloud) Instances() (cloudprovider.Instances, bool) {
+	return nil, false
+}
+
+// Zones is not supported, zones are returned by InstancesV2
+func (c *Cloud) Zones() (cloudprovider.Zones, bool) {
+	return nil, false
+}
+
+// Clusters is not supported
+func (c *Cloud) Clusters() (cloudprovider.Clusters, bool) {
+	return nil, false
+}
+
+// Routes is not supported
+func (c *Cloud) Routes() (cloudprovider.Routes, bool) {
+	return nil, false
+}
+
+// ProviderName returns the name of the cloud provider
+func (c *Cloud) ProviderName() string {
+	return ProviderName
+}
+
+// HasClusterID returns true as no cluster ID is required, instances are only
+// looked up through the provider ID of their Node
+func (c *Cloud) HasClusterID() bool {
+	return true
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cloudprovider "k8s.io/cloud-provider"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestNewCloud(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    client.ObjectKey
+		wantErr string
+	}{
+		{
+			name:   "credentials secret",
+			config: "credentialsSecret: openshift-machine-api/nvidia-carbide-credentials\n",
+			want:   client.ObjectKey{Namespace: "openshift-machine-api", Name: "nvidia-carbide-credentials"},
+		},
+		{
+			name:    "missing credentials secret",
+			config:  "",
+			wantErr: "invalid credentials secret",
+		},
+		{
+			name:    "unknown field",
+			config:  "credentialsSecret: ns/name\nsiteId: site-a\n",
+			wantErr: "failed to parse cloud config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cloud, err := NewCloud(strings.NewReader(tt.config))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cloud.secretKey != tt.want {
+				t.Errorf("expected credentials secret %s, got %s", tt.want, cloud.secretKey)
+			}
+		})
+	}
+}
+
+func TestCloudRegistered(t *testing.T) {
+	cloud, err := cloudprovider.GetCloudProvider(ProviderName,
+		strings.NewReader("credentialsSecret: ns/name\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cloud.ProviderName() != ProviderName {
+		t.Errorf("expected provider name %q, got %q", ProviderName, cloud.ProviderName())
+	}
+	if _, ok := cloud.InstancesV2(); !ok {
+		t.Error("expected InstancesV2 to be supported")
+	}
+	if _, ok := cloud.Instances(); ok {
+		t.Error("expected Instances not to be supported")
+	}
+
+	// Before Initialize no Carbide client can be created
+	instances, _ := cloud.InstancesV2()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+		Spec:       corev1.NodeSpec{ProviderID: testProviderID},
+	}
+	if _, err := instances.InstanceExists(context.Background(), node); err == nil {
+		t.Error("expected an error before the cloud provider is initialized")
+	}
+}
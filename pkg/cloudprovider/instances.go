@@ -0,0 +1,143 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cloudprovider resolves Nodes to NVIDIA Carbide instances through
+// their provider ID, implementing the cloud-provider InstancesV2 interface
+package cloudprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	cloudprovider "k8s.io/cloud-provider"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/providerid"
+	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
+)
+
+// Instances implements the cloud-provider InstancesV2 methods on top of the
+// NVIDIA Carbide instance API
+type Instances struct {
+	getClient func(ctx context.Context) (machine.NvidiaCarbideClientInterface, error)
+}
+
+var _ cloudprovider.InstancesV2 = &Instances{}
+
+// NewInstances returns Instances resolving Nodes with client
+func NewInstances(client machine.NvidiaCarbideClientInterface) *Instances {
+	return &Instances{
+		getClient: func(context.Context) (machine.NvidiaCarbideClientInterface, error) {
+			return client, nil
+		},
+	}
+}
+
+// InstanceExists returns true if the instance of the Node still exists
+func (i *Instances) InstanceExists(ctx context.Context, node *corev1.Node) (bool, error) {
+	_, instance, err := i.getInstance(ctx, node)
+	if err != nil {
+		return false, err
+	}
+	return instance != nil, nil
+}
+
+// InstanceShutdown returns true if the instance of the Node is shut down
+func (i *Instances) InstanceShutdown(ctx context.Context, node *corev1.Node) (bool, error) {
+	_, instance, err := i.getInstance(ctx, node)
+	if err != nil {
+		return false, err
+	}
+	if instance == nil {
+		return false, fmt.Errorf("instance of node %s not found", node.Name)
+	}
+	return instanceShutdown(instance), nil
+}
+
+// InstanceMetadata returns the metadata of the instance of the Node
+func (i *Instances) InstanceMetadata(ctx context.Context, node *corev1.Node) (*cloudprovider.InstanceMetadata, error) {
+	_, instance, err := i.getInstance(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("instance of node %s not found", node.Name)
+	}
+	return instanceMetadata(node, instance), nil
+}
+
+// getInstance returns the instance of the Node, or nil if it does not exist
+func (i *Instances) getInstance(ctx context.Context, node *corev1.Node) (*providerid.ProviderID, *bmm.Instance, error) {
+	if node.Spec.ProviderID == "" {
+		return nil, nil, fmt.Errorf("node %s has no provider ID", node.Name)
+	}
+	pid, err := providerid.ParseProviderID(node.Spec.ProviderID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse provider ID of node %s: %w", node.Name, err)
+	}
+
+	client, err := i.getClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	instance, httpResp, err := client.GetInstance(ctx, pid.OrgName, pid.InstanceID.String())
+	if httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+		return pid, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance == nil {
+		return nil, nil, fmt.Errorf("get instance returned no data, status code: %d", httpResp.StatusCode)
+	}
+	return pid, instance, nil
+}
+
+// instanceShutdown returns true for instances that no longer run their
+// operating system
+func instanceShutdown(instance *bmm.Instance) bool {
+	return instance.Status != nil && *instance.Status == bmm.INSTANCESTATUS_TERMINATING
+}
+
+// instanceMetadata maps an instance to the metadata of its Node. Carbide has no
+// region, so the site is used as the zone. The hostname addresses of the Node
+// are kept.
+func instanceMetadata(node *corev1.Node, instance *bmm.Instance) *cloudprovider.InstanceMetadata {
+	metadata := &cloudprovider.InstanceMetadata{ProviderID: node.Spec.ProviderID}
+	if instance.InstanceTypeId != nil {
+		metadata.InstanceType = *instance.InstanceTypeId
+	}
+	if instance.SiteId != nil {
+		metadata.Zone = *instance.SiteId
+	}
+
+	for _, iface := range instance.Interfaces {
+		for _, ipAddr := range iface.IpAddresses {
+			metadata.NodeAddresses = append(metadata.NodeAddresses, corev1.NodeAddress{
+				Type:    corev1.NodeInternalIP,
+				Address: ipAddr,
+			})
+		}
+	}
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeHostName {
+			metadata.NodeAddresses = append(metadata.NodeAddresses, address)
+		}
+	}
+	return metadata
+}
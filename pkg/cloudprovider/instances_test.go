@@ -0,0 +1,164 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
+)
+
+const (
+	testInstanceID = "6f1c6f3e-8c7a-4d8e-9a2b-0c4f2d6e8a10"
+	testProviderID = "nvidia-carbide://test-org/tenant-a/site-a/" + testInstanceID
+)
+
+// fakeInstanceClient serves a single instance, or 404 when instance is nil
+type fakeInstanceClient struct {
+	machine.NvidiaCarbideClientInterface
+	instance *bmm.Instance
+	err      error
+	org      string
+}
+
+func (f *fakeInstanceClient) GetInstance(
+	_ context.Context, org, instanceID string,
+) (*bmm.Instance, *http.Response, error) {
+	f.org = org
+	if f.err != nil {
+		return nil, &http.Response{StatusCode: http.StatusInternalServerError}, f.err
+	}
+	if f.instance == nil || *f.instance.Id != instanceID {
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("404 Not Found")
+	}
+	return f.instance, &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func newTestInstance(status bmm.InstanceStatus) *bmm.Instance {
+	id, site, instanceType := testInstanceID, "site-a", "it-1"
+	return &bmm.Instance{
+		Id:             &id,
+		SiteId:         &site,
+		InstanceTypeId: &instanceType,
+		Status:         &status,
+		Interfaces:     []bmm.Interface{{IpAddresses: []string{"10.0.0.10", "10.0.1.10"}}},
+	}
+}
+
+func newTestNode(providerID string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+		Spec:       corev1.NodeSpec{ProviderID: providerID},
+		Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{
+			{Type: corev1.NodeHostName, Address: "worker-0"},
+			{Type: corev1.NodeInternalIP, Address: "192.168.0.1"},
+		}},
+	}
+}
+
+func TestInstances(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ready instance", func(t *testing.T) {
+		client := &fakeInstanceClient{instance: newTestInstance(bmm.INSTANCESTATUS_READY)}
+		instances := NewInstances(client)
+		node := newTestNode(testProviderID)
+
+		exists, err := instances.InstanceExists(ctx, node)
+		if err != nil || !exists {
+			t.Errorf("InstanceExists() = %v, %v, want true", exists, err)
+		}
+		if client.org != "test-org" {
+			t.Errorf("looked up instance in org %q, want test-org", client.org)
+		}
+		shutdown, err := instances.InstanceShutdown(ctx, node)
+		if err != nil || shutdown {
+			t.Errorf("InstanceShutdown() = %v, %v, want false", shutdown, err)
+		}
+
+		metadata, err := instances.InstanceMetadata(ctx, node)
+		if err != nil {
+			t.Fatalf("InstanceMetadata() error = %v", err)
+		}
+		if metadata.ProviderID != testProviderID || metadata.InstanceType != "it-1" || metadata.Zone != "site-a" {
+			t.Errorf("InstanceMetadata() = %+v", metadata)
+		}
+		wantAddresses := []corev1.NodeAddress{
+			{Type: corev1.NodeInternalIP, Address: "10.0.0.10"},
+			{Type: corev1.NodeInternalIP, Address: "10.0.1.10"},
+			{Type: corev1.NodeHostName, Address: "worker-0"},
+		}
+		if len(metadata.NodeAddresses) != len(wantAddresses) {
+			t.Fatalf("NodeAddresses = %v, want %v", metadata.NodeAddresses, wantAddresses)
+		}
+		for i := range wantAddresses {
+			if metadata.NodeAddresses[i] != wantAddresses[i] {
+				t.Errorf("NodeAddresses[%d] = %v, want %v", i, metadata.NodeAddresses[i], wantAddresses[i])
+			}
+		}
+	})
+
+	t.Run("terminating instance is shut down", func(t *testing.T) {
+		instances := NewInstances(&fakeInstanceClient{instance: newTestInstance(bmm.INSTANCESTATUS_TERMINATING)})
+		shutdown, err := instances.InstanceShutdown(ctx, newTestNode(testProviderID))
+		if err != nil || !shutdown {
+			t.Errorf("InstanceShutdown() = %v, %v, want true", shutdown, err)
+		}
+	})
+
+	t.Run("deleted instance", func(t *testing.T) {
+		instances := NewInstances(&fakeInstanceClient{})
+		exists, err := instances.InstanceExists(ctx, newTestNode(testProviderID))
+		if err != nil || exists {
+			t.Errorf("InstanceExists() = %v, %v, want false", exists, err)
+		}
+		if _, err := instances.InstanceMetadata(ctx, newTestNode(testProviderID)); err == nil {
+			t.Error("InstanceMetadata() expected an error")
+		}
+	})
+
+	t.Run("legacy provider ID", func(t *testing.T) {
+		instances := NewInstances(&fakeInstanceClient{instance: newTestInstance(bmm.INSTANCESTATUS_READY)})
+		exists, err := instances.InstanceExists(ctx, newTestNode("nvidia-carbide://test-org/site-a/"+testInstanceID))
+		if err != nil || !exists {
+			t.Errorf("InstanceExists() = %v, %v, want true", exists, err)
+		}
+	})
+
+	t.Run("API error is not a missing instance", func(t *testing.T) {
+		instances := NewInstances(&fakeInstanceClient{err: errors.New("500 Internal Server Error")})
+		if _, err := instances.InstanceExists(ctx, newTestNode(testProviderID)); err == nil {
+			t.Error("InstanceExists() expected an error")
+		}
+	})
+
+	t.Run("unresolvable provider IDs", func(t *testing.T) {
+		instances := NewInstances(&fakeInstanceClient{})
+		for _, providerID := range []string{"", "aws:///us-east-1a/i-0123456789"} {
+			if _, err := instances.InstanceExists(ctx, newTestNode(providerID)); err == nil {
+				t.Errorf("InstanceExists(%q) expected an error", providerID)
+			}
+		}
+	})
+}
@@ -19,9 +19,11 @@ package machine
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"go.opentelemetry.io/otel/attribute"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
@@ -31,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/tracing"
 )
 
 const (
@@ -39,6 +42,14 @@ const (
 
 	// RequeueAfterSeconds is the time to wait before requeuing
 	RequeueAfterSeconds = 30
+
+	// InsufficientCapacityRequeueAfter is the time to wait before retrying a
+	// create that failed for lack of free hosts
+	InsufficientCapacityRequeueAfter = 2 * time.Minute
+
+	// InvalidConfigurationRequeueAfter is the time to wait before retrying a
+	// create whose provider spec references unknown Carbide resources
+	InvalidConfigurationRequeueAfter = 5 * time.Minute
 )
 
 // MachineReconciler reconciles OpenShift Machine objects
@@ -47,10 +58,20 @@ type MachineReconciler struct {
 	Scheme        *runtime.Scheme
 	Actuator      *machine.Actuator
 	EventRecorder record.EventRecorder
+	// APIReader lists the pods of a Node without caching every pod of the cluster
+	APIReader client.Reader
+	// Drain configures the drain of the Node before the instance is deleted
+	Drain DrainOptions
 }
 
 // Reconcile handles Machine reconciliation
-func (r *MachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *MachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.StartSpan(ctx, "MachineReconciler.Reconcile",
+		attribute.String("machine.namespace", req.Namespace),
+		attribute.String("machine.name", req.Name),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	logger := log.FromContext(ctx)
 
 	// Fetch the Machine instance
@@ -96,10 +117,38 @@ func (r *MachineReconciler) reconcileNormal(ctx context.Context, machineObj clie
 		// Create instance
 		logger.Info("Creating instance")
 		if err := r.Actuator.Create(ctx, machineObj); err != nil {
+			if machine.IsInsufficientCapacity(err) {
+				// Retrying quickly will not free up hosts, report it and back off
+				logger.Info("Insufficient capacity to create instance", "reason", err.Error())
+				if err := r.setMachineError(ctx, machineObj, machine.InsufficientCapacityMachineError, err.Error()); err != nil {
+					return ctrl.Result{}, err
+				}
+				if err := r.updateMachineSetInsufficientCapacity(ctx, machineObj); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: InsufficientCapacityRequeueAfter}, nil
+			}
+			if machine.IsInvalidConfiguration(err) {
+				// The references will not resolve until the spec or Carbide changes
+				logger.Info("Invalid provider spec", "reason", err.Error())
+				if err := r.setMachineError(
+					ctx, machineObj, machinev1beta1.InvalidConfigurationMachineError, err.Error(),
+				); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: InvalidConfigurationRequeueAfter}, nil
+			}
 			logger.Error(err, "failed to create instance")
 			return ctrl.Result{RequeueAfter: RequeueAfterSeconds * time.Second}, err
 		}
 		logger.Info("Successfully created instance")
+		if err := r.clearMachineError(ctx, machineObj,
+			machine.InsufficientCapacityMachineError, machinev1beta1.InvalidConfigurationMachineError); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.updateMachineSetInsufficientCapacity(ctx, machineObj); err != nil {
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
@@ -110,6 +159,18 @@ func (r *MachineReconciler) reconcileNormal(ctx context.Context, machineObj clie
 		return ctrl.Result{RequeueAfter: RequeueAfterSeconds * time.Second}, err
 	}
 
+	// Handle reboot requests
+	if err := r.Actuator.Reboot(ctx, machineObj); err != nil {
+		logger.Error(err, "failed to handle reboot request")
+		return ctrl.Result{RequeueAfter: RequeueAfterSeconds * time.Second}, err
+	}
+
+	// Handle reprovision requests and boot source changes
+	if err := r.Actuator.Reprovision(ctx, machineObj); err != nil {
+		logger.Error(err, "failed to handle reprovision request")
+		return ctrl.Result{RequeueAfter: RequeueAfterSeconds * time.Second}, err
+	}
+
 	logger.Info("Successfully reconciled Machine")
 	return ctrl.Result{RequeueAfter: RequeueAfterSeconds * time.Second}, nil
 }
@@ -119,6 +180,38 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, machineObj clie
 
 	logger.Info("Deleting Machine")
 
+	if m, ok := machineObj.(*machinev1beta1.Machine); ok {
+		// Keep protected Machines, with their finalizer, untouched
+		held, err := r.checkDeletionProtection(ctx, m)
+		if err != nil || held {
+			return ctrl.Result{}, err
+		}
+
+		// Let operators holding the Machine act before the drain
+		held, err = r.checkLifecycleHooks(ctx, m, machinev1beta1.MachineDrainable, "preDrain",
+			m.Spec.LifecycleHooks.PreDrain)
+		if err != nil || held {
+			return ctrl.Result{}, err
+		}
+
+		// Move the workloads off the Node before its host goes away
+		drained, err := r.drainNode(ctx, m)
+		if err != nil {
+			logger.Error(err, "failed to drain node")
+			return ctrl.Result{}, err
+		}
+		if !drained {
+			return ctrl.Result{RequeueAfter: DrainRequeueAfter}, nil
+		}
+
+		// Let operators holding the Machine act before the instance is deleted
+		held, err = r.checkLifecycleHooks(ctx, m, machinev1beta1.MachineTerminable, "preTerminate",
+			m.Spec.LifecycleHooks.PreTerminate)
+		if err != nil || held {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Delete instance
 	if err := r.Actuator.Delete(ctx, machineObj); err != nil {
 		logger.Error(err, "failed to delete instance")
@@ -131,10 +224,54 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, machineObj clie
 		return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %w", err)
 	}
 
+	// A deleted Machine no longer holds its MachineSet out of capacity
+	if err := r.updateMachineSetInsufficientCapacity(ctx, machineObj); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	logger.Info("Successfully deleted Machine")
 	return ctrl.Result{}, nil
 }
 
+// setMachineError records a terminal-looking error on the Machine status so
+// that MachineSet controllers and autoscalers can react to it
+func (r *MachineReconciler) setMachineError(
+	ctx context.Context, machineObj client.Object, reason machinev1beta1.MachineStatusError, message string,
+) error {
+	m, ok := machineObj.(*machinev1beta1.Machine)
+	if !ok {
+		return nil
+	}
+	if m.Status.ErrorReason != nil && *m.Status.ErrorReason == reason &&
+		m.Status.ErrorMessage != nil && *m.Status.ErrorMessage == message {
+		return nil
+	}
+
+	m.Status.ErrorReason = &reason
+	m.Status.ErrorMessage = &message
+	if err := r.Status().Update(ctx, m); err != nil {
+		return fmt.Errorf("failed to set machine error: %w", err)
+	}
+	return nil
+}
+
+// clearMachineError removes a Machine error previously set with one of reasons
+func (r *MachineReconciler) clearMachineError(
+	ctx context.Context, machineObj client.Object, reasons ...machinev1beta1.MachineStatusError,
+) error {
+	m, ok := machineObj.(*machinev1beta1.Machine)
+	if !ok || m.Status.ErrorReason == nil || !slices.Contains(reasons, *m.Status.ErrorReason) {
+		return nil
+	}
+
+	m.Status.ErrorReason = nil
+	m.Status.ErrorMessage = nil
+	if err := r.Status().Update(ctx, m); err != nil {
+		return fmt.Errorf("failed to clear machine error: %w", err)
+	}
+	return nil
+}
+
 // SetupWithManager sets up the controller with the Manager
 func (r *MachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -143,12 +280,14 @@ func (r *MachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 // SetupMachineController creates and registers the Machine controller with the manager
-func SetupMachineController(mgr ctrl.Manager, actuator *machine.Actuator) error {
+func SetupMachineController(mgr ctrl.Manager, actuator *machine.Actuator, drain DrainOptions) error {
 	reconciler := &MachineReconciler{
 		Client:        mgr.GetClient(),
 		Scheme:        mgr.GetScheme(),
 		Actuator:      actuator,
 		EventRecorder: mgr.GetEventRecorderFor("nvidia-carbide-machine-controller"),
+		APIReader:     mgr.GetAPIReader(),
+		Drain:         drain,
 	}
 
 	return reconciler.SetupWithManager(mgr)
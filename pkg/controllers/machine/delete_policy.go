@@ -0,0 +1,110 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+)
+
+const (
+	// DeleteMachineAnnotation marks a Machine to be removed first when its MachineSet scales down
+	DeleteMachineAnnotation = "machine.openshift.io/delete-machine"
+)
+
+// deletePriority ranks Machines for removal on scale down, higher first
+type deletePriority int
+
+const (
+	// couldDelete is the priority of healthy Machines
+	couldDelete deletePriority = iota
+	// betterDelete is the priority of failed Machines and Machines without a Node
+	betterDelete
+	// mustDelete is the priority of Machines annotated for deletion
+	mustDelete
+)
+
+// machineDeletePriority returns the removal tier of a Machine, independent of the delete policy
+func machineDeletePriority(machineObj *machinev1beta1.Machine) deletePriority {
+	if _, ok := machineObj.Annotations[DeleteMachineAnnotation]; ok {
+		return mustDelete
+	}
+	if machineObj.Status.ErrorReason != nil || machineObj.Status.ErrorMessage != nil {
+		return betterDelete
+	}
+	if machineObj.Status.Phase != nil && *machineObj.Status.Phase == machinev1beta1.PhaseFailed {
+		return betterDelete
+	}
+	if machineObj.Status.NodeRef == nil {
+		return betterDelete
+	}
+	return couldDelete
+}
+
+// getMachinesToDelete returns the count Machines to remove on scale down.
+// Annotated Machines come first, then failed Machines and Machines without a
+// Node; the delete policy orders Machines within each tier.
+func getMachinesToDelete(
+	machines []*machinev1beta1.Machine, count int, policy string,
+) ([]*machinev1beta1.Machine, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	if count > len(machines) {
+		count = len(machines)
+	}
+
+	var less func(a, b *machinev1beta1.Machine) bool
+	switch machinev1beta1.MachineSetDeletePolicy(policy) {
+	case machinev1beta1.NewestMachineSetDeletePolicy:
+		less = func(a, b *machinev1beta1.Machine) bool {
+			return b.CreationTimestamp.Before(&a.CreationTimestamp)
+		}
+	case machinev1beta1.OldestMachineSetDeletePolicy:
+		less = func(a, b *machinev1beta1.Machine) bool {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+	case machinev1beta1.RandomMachineSetDeletePolicy, "":
+		less = nil
+	default:
+		return nil, fmt.Errorf("unsupported delete policy %q", policy)
+	}
+
+	sorted := make([]*machinev1beta1.Machine, len(machines))
+	copy(sorted, machines)
+	if less == nil {
+		rand.Shuffle(len(sorted), func(i, j int) {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		})
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		pi, pj := machineDeletePriority(sorted[i]), machineDeletePriority(sorted[j])
+		if pi != pj {
+			return pi > pj
+		}
+		if less != nil {
+			return less(sorted[i], sorted[j])
+		}
+		return false
+	})
+
+	return sorted[:count], nil
+}
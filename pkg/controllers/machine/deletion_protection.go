@@ -0,0 +1,72 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+)
+
+// DeletionProtectedReason is set on the Terminable condition of a deleted
+// Machine whose instance is protected from deletion
+const DeletionProtectedReason = "DeletionProtected"
+
+// checkDeletionProtection reports whether the deletion of a Machine is held
+// by its deletion protection, and records it in the Terminable condition.
+// The deletion resumes on the Machine update removing the protection.
+func (r *MachineReconciler) checkDeletionProtection(ctx context.Context, m *machinev1beta1.Machine) (bool, error) {
+	protected, err := r.Actuator.DeletionProtected(m)
+	if err != nil {
+		return false, err
+	}
+
+	if !protected {
+		// Drop a stale protection condition, the lifecycle hooks check sets
+		// Terminable again before the instance is deleted
+		for _, condition := range m.Status.Conditions {
+			if condition.Type == machinev1beta1.MachineTerminable && condition.Reason == DeletionProtectedReason {
+				_, err := r.setMachineCondition(ctx, m, machinev1beta1.Condition{
+					Type:   machinev1beta1.MachineTerminable,
+					Status: corev1.ConditionTrue,
+				})
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	message := fmt.Sprintf("The instance is protected from deletion, set deletionProtection to false in the "+
+		"provider spec and remove the %s annotation to delete it", machine.DeletionProtectionAnnotation)
+	log.FromContext(ctx).Info("Machine is protected from deletion, keeping the instance")
+	changed, err := r.setMachineCondition(ctx, m, machinev1beta1.Condition{
+		Type:     machinev1beta1.MachineTerminable,
+		Status:   corev1.ConditionFalse,
+		Severity: machinev1beta1.ConditionSeverityWarning,
+		Reason:   DeletionProtectedReason,
+		Message:  message,
+	})
+	if changed && r.EventRecorder != nil {
+		r.EventRecorder.Event(m, corev1.EventTypeWarning, DeletionProtectedReason, message)
+	}
+	return true, err
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+)
+
+func TestMachineReconciler_deletionProtection(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		spec        string
+		unprotect   func(m *machinev1beta1.Machine)
+	}{
+		{
+			name:        "annotation",
+			annotations: map[string]string{machine.DeletionProtectionAnnotation: "true"},
+			spec:        `{"siteId":"site"}`,
+			unprotect:   func(m *machinev1beta1.Machine) { delete(m.Annotations, machine.DeletionProtectionAnnotation) },
+		},
+		{
+			name: "provider spec",
+			spec: `{"siteId":"site","deletionProtection":true}`,
+			unprotect: func(m *machinev1beta1.Machine) {
+				m.Spec.ProviderSpec.Value = &runtime.RawExtension{Raw: []byte(`{"siteId":"site"}`)}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deletionTimestamp := metav1.NewTime(time.Now())
+			machineObj := &machinev1beta1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "worker-0",
+					Namespace:         "openshift-machine-api",
+					Annotations:       tt.annotations,
+					Finalizers:        []string{MachineFinalizer},
+					DeletionTimestamp: &deletionTimestamp,
+				},
+				Spec: machinev1beta1.MachineSpec{
+					ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{Raw: []byte(tt.spec)}},
+				},
+			}
+
+			scheme := runtime.NewScheme()
+			_ = machinev1beta1.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(machineObj).WithStatusSubresource(machineObj).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &MachineReconciler{
+				Client:        fakeClient,
+				Actuator:      machine.NewActuatorWithClient(fakeClient, nil, nil, "test-org"),
+				EventRecorder: recorder,
+			}
+			key := client.ObjectKeyFromObject(machineObj)
+
+			for range 2 {
+				if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+					t.Fatalf("Reconcile() error = %v", err)
+				}
+			}
+
+			m := &machinev1beta1.Machine{}
+			if err := fakeClient.Get(context.Background(), key, m); err != nil {
+				t.Fatalf("expected the protected Machine to be kept: %v", err)
+			}
+			var terminable *machinev1beta1.Condition
+			for i := range m.Status.Conditions {
+				if m.Status.Conditions[i].Type == machinev1beta1.MachineTerminable {
+					terminable = &m.Status.Conditions[i]
+				}
+			}
+			if terminable == nil || terminable.Status != corev1.ConditionFalse ||
+				terminable.Reason != DeletionProtectedReason {
+				t.Errorf("Terminable condition = %+v, want DeletionProtected", terminable)
+			}
+			if len(recorder.Events) != 1 {
+				t.Fatalf("got %d events, want a single warning", len(recorder.Events))
+			}
+			if event := <-recorder.Events; !strings.HasPrefix(event, corev1.EventTypeWarning+" "+DeletionProtectedReason) {
+				t.Errorf("event = %q, want a DeletionProtected warning", event)
+			}
+
+			tt.unprotect(m)
+			if err := fakeClient.Update(context.Background(), m); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := fakeClient.Get(context.Background(), key, m); !errors.IsNotFound(err) {
+				t.Errorf("expected the Machine to be deleted once unprotected, got %v", err)
+			}
+		})
+	}
+}
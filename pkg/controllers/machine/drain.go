@@ -0,0 +1,275 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// ExcludeNodeDrainingAnnotation skips draining the Node of a Machine
+	// before its instance is deleted
+	ExcludeNodeDrainingAnnotation = "machine.openshift.io/exclude-node-draining"
+
+	// DefaultSkipWaitForDeleteTimeout is how long a drain waits for an evicted
+	// pod to terminate before ignoring it
+	DefaultSkipWaitForDeleteTimeout = 5 * time.Minute
+
+	// DrainRequeueAfter is the time to wait before checking the progress of a drain
+	DrainRequeueAfter = 20 * time.Second
+
+	// mirrorPodAnnotation marks static pods mirrored by the kubelet, which
+	// cannot be evicted
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+	// podNodeNameField selects the pods scheduled on a Node
+	podNodeNameField = "spec.nodeName"
+)
+
+// Drained condition reasons, on top of machinev1beta1.MachineDrainError
+const (
+	// DrainingReason is set while pods are being evicted from the Node
+	DrainingReason = "Draining"
+
+	// NodeDrainedReason is set once all pods left the Node
+	NodeDrainedReason = "NodeDrained"
+
+	// DrainSkippedReason is set when the Machine opted out of draining
+	DrainSkippedReason = "DrainSkipped"
+
+	// DrainTimeoutReason is set when the instance is deleted before all pods
+	// left the Node
+	DrainTimeoutReason = "DrainTimeout"
+)
+
+// DrainOptions configures the drain of the Node of a Machine before its
+// instance is deleted
+type DrainOptions struct {
+	// Timeout bounds how long a drain may take before the instance is deleted
+	// anyway, 0 waits until all pods left the Node
+	Timeout time.Duration
+
+	// SkipWaitForDeleteTimeout ignores pods deleted longer ago than this, as
+	// their Node may no longer be able to stop them
+	SkipWaitForDeleteTimeout time.Duration
+}
+
+// drainNode cordons the Node of a Machine and evicts its pods, honouring
+// PodDisruptionBudgets. It reports whether the instance can be deleted.
+func (r *MachineReconciler) drainNode(ctx context.Context, m *machinev1beta1.Machine) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	if _, ok := m.Annotations[ExcludeNodeDrainingAnnotation]; ok {
+		logger.Info("Skipping node drain", "annotation", ExcludeNodeDrainingAnnotation)
+		_, err := r.setMachineCondition(ctx, m, drainedCondition(corev1.ConditionTrue, DrainSkippedReason,
+			fmt.Sprintf("Node draining is excluded by the %s annotation", ExcludeNodeDrainingAnnotation)))
+		return true, err
+	}
+	if m.Status.NodeRef == nil {
+		return true, nil
+	}
+
+	node := &corev1.Node{}
+	if err := r.Get(ctx, client.ObjectKey{Name: m.Status.NodeRef.Name}, node); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to get node: %w", err)
+	}
+
+	if !node.Spec.Unschedulable {
+		patch := client.MergeFrom(node.DeepCopy())
+		node.Spec.Unschedulable = true
+		if err := r.Patch(ctx, node, patch); err != nil {
+			return false, fmt.Errorf("failed to cordon node: %w", err)
+		}
+		logger.Info("Cordoned node", "node", node.Name)
+	}
+
+	pending, blocked, err := r.evictPods(ctx, node.Name)
+	if err != nil {
+		condition := drainedCondition(corev1.ConditionFalse, machinev1beta1.MachineDrainError, err.Error())
+		if _, condErr := r.setMachineCondition(ctx, m, condition); condErr != nil {
+			logger.Error(condErr, "failed to set drain condition")
+		}
+		return false, err
+	}
+
+	if len(pending) == 0 {
+		changed, err := r.setMachineCondition(ctx, m, drainedCondition(corev1.ConditionTrue, NodeDrainedReason,
+			fmt.Sprintf("Drained node %s", node.Name)))
+		if changed && r.EventRecorder != nil {
+			r.EventRecorder.Eventf(m, corev1.EventTypeNormal, "Drained", "Drained node %s", node.Name)
+		}
+		return true, err
+	}
+
+	message := fmt.Sprintf("Waiting for %d pods to leave node %s", len(pending), node.Name)
+	if len(blocked) > 0 {
+		message += fmt.Sprintf(", evictions blocked by PodDisruptionBudgets: %s", strings.Join(blocked, ", "))
+	}
+
+	// The deletion may have been held by lifecycle hooks, so the timeout runs
+	// from the first Draining condition rather than from the deletion
+	if started := drainStartTime(m); r.Drain.Timeout > 0 && started != nil &&
+		time.Since(started.Time) > r.Drain.Timeout {
+		message = fmt.Sprintf("Drain of node %s timed out after %s with %d pods left: %s",
+			node.Name, r.Drain.Timeout, len(pending), strings.Join(pending, ", "))
+		changed, err := r.setMachineCondition(ctx, m,
+			drainedCondition(corev1.ConditionFalse, DrainTimeoutReason, message))
+		if changed && r.EventRecorder != nil {
+			r.EventRecorder.Event(m, corev1.EventTypeWarning, DrainTimeoutReason, message)
+		}
+		return true, err
+	}
+
+	logger.Info("Draining node", "node", node.Name, "pending", len(pending), "blocked", len(blocked))
+	_, err = r.setMachineCondition(ctx, m, drainedCondition(corev1.ConditionFalse, DrainingReason, message))
+	return false, err
+}
+
+// evictPods requests the eviction of the pods of a Node. It returns the pods
+// still on the Node and those whose eviction a PodDisruptionBudget refused.
+func (r *MachineReconciler) evictPods(ctx context.Context, nodeName string) ([]string, []string, error) {
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	pods := &corev1.PodList{}
+	if err := reader.List(ctx, pods, client.MatchingFields{podNodeNameField: nodeName}); err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods of node %s: %w", nodeName, err)
+	}
+
+	skipWaitForDeleteTimeout := r.Drain.SkipWaitForDeleteTimeout
+	if skipWaitForDeleteTimeout == 0 {
+		skipWaitForDeleteTimeout = DefaultSkipWaitForDeleteTimeout
+	}
+
+	var pending, blocked []string
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !evictable(pod) {
+			continue
+		}
+		key := client.ObjectKeyFromObject(pod).String()
+		if pod.DeletionTimestamp != nil {
+			if time.Since(pod.DeletionTimestamp.Time) < skipWaitForDeleteTimeout {
+				pending = append(pending, key)
+			}
+			continue
+		}
+
+		eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+		if err := r.SubResource("eviction").Create(ctx, pod, eviction); err != nil {
+			switch {
+			case errors.IsNotFound(err):
+				continue
+			case errors.IsTooManyRequests(err):
+				blocked = append(blocked, key)
+			default:
+				return nil, nil, fmt.Errorf("failed to evict pod %s: %w", key, err)
+			}
+		}
+		pending = append(pending, key)
+	}
+	return pending, blocked, nil
+}
+
+// evictable reports whether a drain evicts a pod. DaemonSet pods would be
+// recreated on the cordoned Node and mirror pods cannot be evicted.
+func evictable(pod *corev1.Pod) bool {
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return false
+	}
+	if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "DaemonSet" {
+		return false
+	}
+	return true
+}
+
+// drainStartTime returns when the drain of the Node of a Machine started, the
+// time the Drained condition last went False, or nil before the first attempt
+func drainStartTime(m *machinev1beta1.Machine) *metav1.Time {
+	for i := range m.Status.Conditions {
+		condition := &m.Status.Conditions[i]
+		if condition.Type == machinev1beta1.MachineDrained && condition.Status == corev1.ConditionFalse {
+			return &condition.LastTransitionTime
+		}
+	}
+	return nil
+}
+
+// drainedCondition builds the Drained Machine condition
+func drainedCondition(status corev1.ConditionStatus, reason, message string) machinev1beta1.Condition {
+	severity := machinev1beta1.ConditionSeverityNone
+	switch reason {
+	case DrainingReason:
+		severity = machinev1beta1.ConditionSeverityInfo
+	case DrainTimeoutReason, machinev1beta1.MachineDrainError:
+		severity = machinev1beta1.ConditionSeverityWarning
+	}
+	return machinev1beta1.Condition{
+		Type:     machinev1beta1.MachineDrained,
+		Status:   status,
+		Severity: severity,
+		Reason:   reason,
+		Message:  message,
+	}
+}
+
+// setMachineCondition sets a condition in the Machine status, writing the
+// status only when the condition changed, and reports whether it changed
+func (r *MachineReconciler) setMachineCondition(
+	ctx context.Context, m *machinev1beta1.Machine, condition machinev1beta1.Condition,
+) (bool, error) {
+	condition.LastTransitionTime = metav1.Now()
+	found := false
+	for i := range m.Status.Conditions {
+		existing := &m.Status.Conditions[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status == condition.Status && existing.Reason == condition.Reason &&
+			existing.Message == condition.Message && existing.Severity == condition.Severity {
+			return false, nil
+		}
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+		*existing = condition
+		found = true
+	}
+	if !found {
+		m.Status.Conditions = append(m.Status.Conditions, condition)
+	}
+
+	if err := r.Status().Update(ctx, m); err != nil {
+		return false, fmt.Errorf("failed to set machine condition %s: %w", condition.Type, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newDrainPod(name string, mutate func(pod *corev1.Pod)) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+		Spec:       corev1.PodSpec{NodeName: "node-0"},
+	}
+	if mutate != nil {
+		mutate(pod)
+	}
+	return pod
+}
+
+func TestMachineReconciler_drainNode(t *testing.T) {
+	appPod := newDrainPod("app", nil)
+	isController := true
+	daemonSetPod := newDrainPod("agent", func(pod *corev1.Pod) {
+		pod.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: "DaemonSet", Name: "agent", UID: "ds", Controller: &isController,
+		}}
+	})
+	mirrorPod := newDrainPod("static", func(pod *corev1.Pod) {
+		pod.Annotations = map[string]string{mirrorPodAnnotation: "hash"}
+	})
+	pdbBlocked := interceptor.Funcs{
+		SubResourceCreate: func(_ context.Context, _ client.Client, _ string, _, _ client.Object,
+			_ ...client.SubResourceCreateOption) error {
+			return errors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+		},
+	}
+
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		noNodeRef    bool
+		deletedAgo   time.Duration
+		drainingFor  time.Duration
+		timeout      time.Duration
+		interceptor  *interceptor.Funcs
+		wantDrained  []bool
+		wantReason   string
+		wantMessage  string
+		wantCordoned bool
+		wantPods     []string
+	}{
+		{
+			name:         "evicts pods then completes",
+			wantDrained:  []bool{false, true},
+			wantReason:   NodeDrainedReason,
+			wantCordoned: true,
+			wantPods:     []string{"agent", "static"},
+		},
+		{
+			name:         "eviction blocked by a PodDisruptionBudget",
+			interceptor:  &pdbBlocked,
+			wantDrained:  []bool{false, false},
+			wantReason:   DrainingReason,
+			wantMessage:  "blocked by PodDisruptionBudgets: apps/app",
+			wantCordoned: true,
+			wantPods:     []string{"agent", "app", "static"},
+		},
+		{
+			name:         "timeout lets the deletion proceed",
+			interceptor:  &pdbBlocked,
+			deletedAgo:   time.Hour,
+			drainingFor:  time.Hour,
+			timeout:      10 * time.Minute,
+			wantDrained:  []bool{true},
+			wantReason:   DrainTimeoutReason,
+			wantMessage:  "apps/app",
+			wantCordoned: true,
+			wantPods:     []string{"agent", "app", "static"},
+		},
+		{
+			name:         "timeout runs from the start of the drain",
+			interceptor:  &pdbBlocked,
+			deletedAgo:   time.Hour,
+			timeout:      10 * time.Minute,
+			wantDrained:  []bool{false, false},
+			wantReason:   DrainingReason,
+			wantCordoned: true,
+			wantPods:     []string{"agent", "app", "static"},
+		},
+		{
+			name:        "excluded by annotation",
+			annotations: map[string]string{ExcludeNodeDrainingAnnotation: ""},
+			wantDrained: []bool{true},
+			wantReason:  DrainSkippedReason,
+			wantPods:    []string{"agent", "app", "static"},
+		},
+		{
+			name:        "no node",
+			noNodeRef:   true,
+			wantDrained: []bool{true},
+			wantPods:    []string{"agent", "app", "static"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deletionTimestamp := metav1.NewTime(time.Now().Add(-tt.deletedAgo))
+			machineObj := &machinev1beta1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "worker-0",
+					Namespace:         "openshift-machine-api",
+					Annotations:       tt.annotations,
+					Finalizers:        []string{MachineFinalizer},
+					DeletionTimestamp: &deletionTimestamp,
+				},
+			}
+			if tt.drainingFor > 0 {
+				machineObj.Status.Conditions = []machinev1beta1.Condition{drainedCondition(
+					corev1.ConditionFalse, DrainingReason, "Waiting for 1 pods to leave node node-0")}
+				machineObj.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-tt.drainingFor))
+			}
+			if !tt.noNodeRef {
+				machineObj.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: "node-0"}
+			}
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}}
+
+			scheme := runtime.NewScheme()
+			_ = machinev1beta1.AddToScheme(scheme)
+			_ = corev1.AddToScheme(scheme)
+			builder := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(machineObj, node, appPod.DeepCopy(), daemonSetPod.DeepCopy(), mirrorPod.DeepCopy()).
+				WithStatusSubresource(machineObj).
+				WithIndex(&corev1.Pod{}, podNodeNameField, func(obj client.Object) []string {
+					return []string{obj.(*corev1.Pod).Spec.NodeName}
+				})
+			if tt.interceptor != nil {
+				builder = builder.WithInterceptorFuncs(*tt.interceptor)
+			}
+			fakeClient := builder.Build()
+			r := &MachineReconciler{
+				Client:        fakeClient,
+				EventRecorder: record.NewFakeRecorder(10),
+				Drain:         DrainOptions{Timeout: tt.timeout},
+			}
+
+			m := &machinev1beta1.Machine{}
+			for i, want := range tt.wantDrained {
+				if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(machineObj), m); err != nil {
+					t.Fatal(err)
+				}
+				drained, err := r.drainNode(context.Background(), m)
+				if err != nil {
+					t.Fatalf("drainNode() call %d error = %v", i, err)
+				}
+				if drained != want {
+					t.Errorf("drainNode() call %d = %t, want %t", i, drained, want)
+				}
+			}
+
+			var condition *machinev1beta1.Condition
+			for i := range m.Status.Conditions {
+				if m.Status.Conditions[i].Type == machinev1beta1.MachineDrained {
+					condition = &m.Status.Conditions[i]
+				}
+			}
+			switch {
+			case tt.wantReason == "" && condition != nil:
+				t.Errorf("expected no Drained condition, got %+v", condition)
+			case tt.wantReason != "" && (condition == nil || condition.Reason != tt.wantReason):
+				t.Errorf("Drained condition = %+v, want reason %s", condition, tt.wantReason)
+			case tt.wantMessage != "" && !strings.Contains(condition.Message, tt.wantMessage):
+				t.Errorf("Drained message = %q, want it to contain %q", condition.Message, tt.wantMessage)
+			}
+
+			gotNode := &corev1.Node{}
+			if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(node), gotNode); err != nil {
+				t.Fatal(err)
+			}
+			if gotNode.Spec.Unschedulable != tt.wantCordoned {
+				t.Errorf("node unschedulable = %t, want %t", gotNode.Spec.Unschedulable, tt.wantCordoned)
+			}
+
+			pods := &corev1.PodList{}
+			if err := fakeClient.List(context.Background(), pods); err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, pod := range pods.Items {
+				names = append(names, pod.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantPods, ",") {
+				t.Errorf("remaining pods = %v, want %v", names, tt.wantPods)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+)
+
+const (
+	// InsufficientCapacityAnnotation is set on a MachineSet while one of its
+	// Machines cannot be created because the site has no free host of the
+	// instance type. Its value is the error message of that Machine.
+	InsufficientCapacityAnnotation = "nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/insufficient-capacity"
+)
+
+// updateMachineSetInsufficientCapacity sets the insufficient capacity
+// annotation on the MachineSet owning the Machine while one of its Machines
+// failed to be created for lack of hosts, and removes it once none has
+func (r *MachineReconciler) updateMachineSetInsufficientCapacity(ctx context.Context, machineObj client.Object) error {
+	m, ok := machineObj.(*machinev1beta1.Machine)
+	if !ok {
+		return nil
+	}
+	controllerRef := metav1.GetControllerOf(m)
+	if controllerRef == nil || controllerRef.Kind != machineSetKind.Kind {
+		return nil
+	}
+
+	machineSet := &machinev1beta1.MachineSet{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: controllerRef.Name}, machineSet); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get MachineSet: %w", err)
+	}
+	if machineSet.UID != controllerRef.UID {
+		return nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&machineSet.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("failed to parse MachineSet selector: %w", err)
+	}
+
+	machineList := &machinev1beta1.MachineList{}
+	if err := r.List(ctx, machineList,
+		client.InNamespace(machineSet.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return fmt.Errorf("failed to list machines: %w", err)
+	}
+
+	message := ""
+	for i := range machineList.Items {
+		member := &machineList.Items[i]
+		// The cache may not have caught up with the status just written
+		if member.UID == m.UID {
+			member = m
+		}
+		ref := metav1.GetControllerOf(member)
+		if ref == nil || ref.UID != machineSet.UID || !member.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if member.Status.ErrorReason != nil &&
+			*member.Status.ErrorReason == machine.InsufficientCapacityMachineError {
+			message = string(machine.InsufficientCapacityMachineError)
+			if member.Status.ErrorMessage != nil {
+				message = *member.Status.ErrorMessage
+			}
+			break
+		}
+	}
+
+	current, annotated := machineSet.Annotations[InsufficientCapacityAnnotation]
+	if (message == "" && !annotated) || (message != "" && current == message) {
+		return nil
+	}
+
+	patchBase := client.MergeFrom(machineSet.DeepCopy())
+	if message == "" {
+		delete(machineSet.Annotations, InsufficientCapacityAnnotation)
+	} else {
+		if machineSet.Annotations == nil {
+			machineSet.Annotations = map[string]string{}
+		}
+		machineSet.Annotations[InsufficientCapacityAnnotation] = message
+	}
+	if err := r.Patch(ctx, machineSet, patchBase); err != nil {
+		return fmt.Errorf("failed to patch MachineSet insufficient capacity annotation: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Updated MachineSet insufficient capacity annotation",
+		"machineSet", machineSet.Name,
+		"insufficientCapacity", message != "")
+	return nil
+}
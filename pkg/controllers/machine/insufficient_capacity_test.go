@@ -0,0 +1,123 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+)
+
+func TestMachineReconciler_updateMachineSetInsufficientCapacity(t *testing.T) {
+	machineSet := newTestMachineSet(2)
+	machineObj := &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "workers-abcde",
+			Namespace:       machineSet.Namespace,
+			UID:             "workers-abcde-uid",
+			Labels:          map[string]string{testMachineSetLabel: "workers"},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(machineSet, machineSetKind)},
+			Finalizers:      []string{MachineFinalizer},
+		},
+	}
+	other := &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "workers-fghij",
+			Namespace:       machineSet.Namespace,
+			UID:             "workers-fghij-uid",
+			Labels:          map[string]string{testMachineSetLabel: "workers"},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(machineSet, machineSetKind)},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = machinev1beta1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(machineSet, machineObj, other).
+		WithStatusSubresource(&machinev1beta1.Machine{}).
+		Build()
+	r := &MachineReconciler{Client: fakeClient, Scheme: scheme}
+
+	getAnnotation := func() (string, bool) {
+		t.Helper()
+		updated := &machinev1beta1.MachineSet{}
+		if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(machineSet), updated); err != nil {
+			t.Fatal(err)
+		}
+		value, ok := updated.Annotations[InsufficientCapacityAnnotation]
+		return value, ok
+	}
+
+	// The Machine failing to be created for lack of hosts annotates its MachineSet
+	message := "no host of instance type it-1 is available in site site"
+	if err := r.setMachineError(context.Background(), machineObj,
+		machine.InsufficientCapacityMachineError, message); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.updateMachineSetInsufficientCapacity(context.Background(), machineObj); err != nil {
+		t.Fatalf("updateMachineSetInsufficientCapacity() error = %v", err)
+	}
+	if value, ok := getAnnotation(); !ok || value != message {
+		t.Errorf("expected annotation %s=%q, got %q", InsufficientCapacityAnnotation, message, value)
+	}
+
+	// Another Machine of the MachineSet being created keeps the annotation
+	if err := r.updateMachineSetInsufficientCapacity(context.Background(), other); err != nil {
+		t.Fatalf("updateMachineSetInsufficientCapacity() error = %v", err)
+	}
+	if _, ok := getAnnotation(); !ok {
+		t.Errorf("expected annotation %s to be kept", InsufficientCapacityAnnotation)
+	}
+
+	// The annotation is removed once the Machine was created
+	if err := r.clearMachineError(context.Background(), machineObj,
+		machine.InsufficientCapacityMachineError); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.updateMachineSetInsufficientCapacity(context.Background(), machineObj); err != nil {
+		t.Fatalf("updateMachineSetInsufficientCapacity() error = %v", err)
+	}
+	if _, ok := getAnnotation(); ok {
+		t.Errorf("expected annotation %s to be removed", InsufficientCapacityAnnotation)
+	}
+
+	// A Machine deleted while waiting for capacity no longer holds the annotation
+	if err := r.setMachineError(context.Background(), machineObj,
+		machine.InsufficientCapacityMachineError, message); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.updateMachineSetInsufficientCapacity(context.Background(), machineObj); err != nil {
+		t.Fatal(err)
+	}
+	deletionTimestamp := metav1.NewTime(time.Now())
+	machineObj.DeletionTimestamp = &deletionTimestamp
+	if err := r.updateMachineSetInsufficientCapacity(context.Background(), machineObj); err != nil {
+		t.Fatalf("updateMachineSetInsufficientCapacity() error = %v", err)
+	}
+	if _, ok := getAnnotation(); ok {
+		t.Errorf("expected annotation %s to be removed for a deleted Machine", InsufficientCapacityAnnotation)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// checkLifecycleHooks reports whether lifecycle hooks hold the deletion of a
+// Machine, and records it in the Drainable or Terminable condition. The
+// deletion resumes on the Machine update removing the last hook.
+func (r *MachineReconciler) checkLifecycleHooks(
+	ctx context.Context, m *machinev1beta1.Machine, conditionType machinev1beta1.ConditionType,
+	stage string, hooks []machinev1beta1.LifecycleHook,
+) (bool, error) {
+	condition := lifecycleHookCondition(conditionType, stage, hooks)
+	changed, err := r.setMachineCondition(ctx, m, condition)
+	if err != nil {
+		return false, err
+	}
+	if len(hooks) == 0 {
+		return false, nil
+	}
+
+	log.FromContext(ctx).Info("Waiting for lifecycle hooks", "stage", stage, "owners", hookOwners(hooks))
+	if changed && r.EventRecorder != nil {
+		r.EventRecorder.Event(m, corev1.EventTypeNormal, machinev1beta1.MachineHookPresent, condition.Message)
+	}
+	return true, nil
+}
+
+// lifecycleHookCondition builds the condition reporting whether hooks of a
+// stage block the deletion of a Machine
+func lifecycleHookCondition(
+	conditionType machinev1beta1.ConditionType, stage string, hooks []machinev1beta1.LifecycleHook,
+) machinev1beta1.Condition {
+	if len(hooks) == 0 {
+		return machinev1beta1.Condition{
+			Type:   conditionType,
+			Status: corev1.ConditionTrue,
+		}
+	}
+
+	described := make([]string, 0, len(hooks))
+	for _, hook := range hooks {
+		described = append(described, fmt.Sprintf("%s (owner %s)", hook.Name, hook.Owner))
+	}
+	return machinev1beta1.Condition{
+		Type:     conditionType,
+		Status:   corev1.ConditionFalse,
+		Severity: machinev1beta1.ConditionSeverityInfo,
+		Reason:   machinev1beta1.MachineHookPresent,
+		Message:  fmt.Sprintf("Waiting for %s hooks to be removed: %s", stage, strings.Join(described, ", ")),
+	}
+}
+
+// hookOwners returns the distinct owners of lifecycle hooks
+func hookOwners(hooks []machinev1beta1.LifecycleHook) []string {
+	var owners []string
+	seen := map[string]bool{}
+	for _, hook := range hooks {
+		if !seen[hook.Owner] {
+			seen[hook.Owner] = true
+			owners = append(owners, hook.Owner)
+		}
+	}
+	return owners
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+)
+
+func TestMachineReconciler_lifecycleHooks(t *testing.T) {
+	deletionTimestamp := metav1.NewTime(time.Now())
+	machineObj := &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "master-0",
+			Namespace:         "openshift-machine-api",
+			Finalizers:        []string{MachineFinalizer},
+			DeletionTimestamp: &deletionTimestamp,
+		},
+		Spec: machinev1beta1.MachineSpec{
+			LifecycleHooks: machinev1beta1.LifecycleHooks{
+				PreDrain:     []machinev1beta1.LifecycleHook{{Name: "EtcdQuorumOperator", Owner: "clusteroperator/etcd"}},
+				PreTerminate: []machinev1beta1.LifecycleHook{{Name: "Backup", Owner: "backup-operator"}},
+			},
+			ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{
+				Raw: []byte(`{"siteId":"site","credentialsSecret":{"name":"creds"}}`),
+			}},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = machinev1beta1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(machineObj).WithStatusSubresource(machineObj).Build()
+	r := &MachineReconciler{
+		Client:        fakeClient,
+		Actuator:      machine.NewActuatorWithClient(fakeClient, nil, nil, "test-org"),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+	key := client.ObjectKeyFromObject(machineObj)
+
+	findCondition := func(
+		m *machinev1beta1.Machine, conditionType machinev1beta1.ConditionType,
+	) *machinev1beta1.Condition {
+		for i := range m.Status.Conditions {
+			if m.Status.Conditions[i].Type == conditionType {
+				return &m.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	steps := []struct {
+		name       string
+		mutate     func(m *machinev1beta1.Machine)
+		wantHeld   machinev1beta1.ConditionType
+		wantOwner  string
+		wantPassed []machinev1beta1.ConditionType
+	}{
+		{
+			name:      "preDrain hook holds the drain",
+			wantHeld:  machinev1beta1.MachineDrainable,
+			wantOwner: "clusteroperator/etcd",
+		},
+		{
+			name:       "preTerminate hook holds the instance deletion",
+			mutate:     func(m *machinev1beta1.Machine) { m.Spec.LifecycleHooks.PreDrain = nil },
+			wantHeld:   machinev1beta1.MachineTerminable,
+			wantOwner:  "backup-operator",
+			wantPassed: []machinev1beta1.ConditionType{machinev1beta1.MachineDrainable},
+		},
+		{
+			name:   "deletion completes once the hooks are removed",
+			mutate: func(m *machinev1beta1.Machine) { m.Spec.LifecycleHooks.PreTerminate = nil },
+		},
+	}
+
+	for _, step := range steps {
+		m := &machinev1beta1.Machine{}
+		if err := fakeClient.Get(context.Background(), key, m); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if step.mutate != nil {
+			step.mutate(m)
+			if err := fakeClient.Update(context.Background(), m); err != nil {
+				t.Fatalf("%s: %v", step.name, err)
+			}
+		}
+
+		if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("%s: Reconcile() error = %v", step.name, err)
+		}
+
+		m = &machinev1beta1.Machine{}
+		err := fakeClient.Get(context.Background(), key, m)
+		if step.wantHeld == "" {
+			if !errors.IsNotFound(err) {
+				t.Errorf("%s: expected the Machine to be deleted, got %v", step.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		held := findCondition(m, step.wantHeld)
+		if held == nil || held.Status != corev1.ConditionFalse || held.Reason != machinev1beta1.MachineHookPresent ||
+			!strings.Contains(held.Message, step.wantOwner) {
+			t.Errorf("%s: %s condition = %+v, want HookPresent naming %s", step.name, step.wantHeld, held, step.wantOwner)
+		}
+		for _, conditionType := range step.wantPassed {
+			if passed := findCondition(m, conditionType); passed == nil || passed.Status != corev1.ConditionTrue {
+				t.Errorf("%s: %s condition = %+v, want True", step.name, conditionType, passed)
+			}
+		}
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+	ncpv1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+)
+
+const (
+	// VCPUAnnotation is the number of vCPUs of a MachineSet host, used by the
+	// cluster-autoscaler to scale from zero
+	VCPUAnnotation = "machine.openshift.io/vCPU"
+
+	// MemoryAnnotation is the memory of a MachineSet host in MiB
+	MemoryAnnotation = "machine.openshift.io/memoryMb"
+
+	// GPUAnnotation is the number of GPUs of a MachineSet host
+	GPUAnnotation = "machine.openshift.io/GPU"
+
+	// MaxPodsAnnotation is the maximum number of pods of a MachineSet host
+	MaxPodsAnnotation = "machine.openshift.io/maxPods"
+
+	// DefaultMaxPods is the kubelet default maxPods on OpenShift
+	DefaultMaxPods = 250
+
+	// DefaultCapacityRefreshInterval is how long instance type capacities are cached
+	DefaultCapacityRefreshInterval = 10 * time.Minute
+)
+
+// capacityCacheEntry is an instance type capacity and when it was fetched
+type capacityCacheEntry struct {
+	capacity  *machine.InstanceTypeCapacity
+	fetchedAt time.Time
+}
+
+// MachineSetCapacityReconciler keeps the scale-from-zero capacity annotations
+// of MachineSets in sync with their Carbide instance type
+type MachineSetCapacityReconciler struct {
+	client.Client
+	Actuator *machine.Actuator
+
+	// MaxPods is the value of the maxPods annotation
+	MaxPods int
+
+	// RefreshInterval is how long an instance type lookup is reused
+	RefreshInterval time.Duration
+
+	mu    sync.Mutex
+	cache map[machine.InstanceTypeKey]capacityCacheEntry
+}
+
+// Reconcile sets the capacity annotations of a MachineSet from its instance type
+func (r *MachineSetCapacityReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	machineSet := &machinev1beta1.MachineSet{}
+	if err := r.Get(ctx, req.NamespacedName, machineSet); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !machineSet.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// MachineSets of other providers are not decoded
+	providerSpecValue := machineSet.Spec.Template.Spec.ProviderSpec.Value
+	if own, err := machine.IsOwnProviderSpec(providerSpecValue); err != nil || !own {
+		return ctrl.Result{}, err
+	}
+	providerSpec, err := machine.ProviderSpecFromRawExtension(providerSpecValue)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get provider spec: %w", err)
+	}
+	if providerSpec.InstanceTypeID == "" {
+		// Targeting a specific machine without an instance type
+		return ctrl.Result{}, nil
+	}
+
+	capacity, err := r.getCapacity(ctx, machineSet.Namespace, providerSpec)
+	if err != nil {
+		logger.Error(err, "failed to get instance type capacity", "instanceTypeId", providerSpec.InstanceTypeID)
+		return ctrl.Result{}, err
+	}
+
+	annotations := map[string]string{
+		VCPUAnnotation:    strconv.FormatInt(capacity.VCPU, 10),
+		MemoryAnnotation:  strconv.FormatInt(capacity.MemoryMiB, 10),
+		GPUAnnotation:     strconv.FormatInt(capacity.GPU, 10),
+		MaxPodsAnnotation: strconv.Itoa(r.maxPods()),
+	}
+
+	patchBase := client.MergeFrom(machineSet.DeepCopy())
+	changed := false
+	for key, value := range annotations {
+		if machineSet.Annotations[key] != value {
+			if machineSet.Annotations == nil {
+				machineSet.Annotations = map[string]string{}
+			}
+			machineSet.Annotations[key] = value
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := r.Patch(ctx, machineSet, patchBase); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to patch MachineSet capacity annotations: %w", err)
+		}
+		logger.Info("Updated MachineSet capacity annotations",
+			"machineSet", machineSet.Name,
+			"vCPU", capacity.VCPU,
+			"memoryMb", capacity.MemoryMiB,
+			"GPU", capacity.GPU)
+	}
+
+	// Pick up instance type changes on the Carbide side
+	return ctrl.Result{RequeueAfter: r.refreshInterval()}, nil
+}
+
+// getCapacity returns the capacity of the provider spec instance type,
+// from the cache while it is fresh. Entries are per organization and
+// credentials Secret, not only per instance type.
+func (r *MachineSetCapacityReconciler) getCapacity(
+	ctx context.Context, namespace string, providerSpec *ncpv1.NvidiaCarbideMachineProviderSpec,
+) (*machine.InstanceTypeCapacity, error) {
+	key, err := r.Actuator.GetInstanceTypeKey(ctx, namespace, providerSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	entry, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < r.refreshInterval() {
+		return entry.capacity, nil
+	}
+
+	capacity, err := r.Actuator.GetInstanceTypeCapacity(ctx, namespace, providerSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cache == nil {
+		r.cache = map[machine.InstanceTypeKey]capacityCacheEntry{}
+	}
+	r.cache[key] = capacityCacheEntry{capacity: capacity, fetchedAt: time.Now()}
+
+	return capacity, nil
+}
+
+func (r *MachineSetCapacityReconciler) maxPods() int {
+	if r.MaxPods > 0 {
+		return r.MaxPods
+	}
+	return DefaultMaxPods
+}
+
+func (r *MachineSetCapacityReconciler) refreshInterval() time.Duration {
+	if r.RefreshInterval > 0 {
+		return r.RefreshInterval
+	}
+	return DefaultCapacityRefreshInterval
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *MachineSetCapacityReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("machineset-capacity").
+		For(&machinev1beta1.MachineSet{}).
+		Complete(r)
+}
+
+// SetupMachineSetCapacityController creates and registers the MachineSet
+// capacity controller with the manager
+func SetupMachineSetCapacityController(mgr ctrl.Manager, actuator *machine.Actuator) error {
+	reconciler := &MachineSetCapacityReconciler{
+		Client:   mgr.GetClient(),
+		Actuator: actuator,
+	}
+
+	return reconciler.SetupWithManager(mgr)
+}
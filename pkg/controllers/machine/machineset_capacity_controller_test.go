@@ -0,0 +1,201 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
+)
+
+// fakeInstanceTypeClient serves a single instance type and counts lookups
+type fakeInstanceTypeClient struct {
+	machine.NvidiaCarbideClientInterface
+	instanceType *bmm.InstanceType
+	calls        int
+}
+
+func (f *fakeInstanceTypeClient) GetInstanceType(
+	_ context.Context, _ string, _ string,
+) (*bmm.InstanceType, *http.Response, error) {
+	f.calls++
+	return f.instanceType, &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func TestMachineSetCapacityReconciler(t *testing.T) {
+	cpu, memory, gpu := "CPU", "Memory", "GPU"
+	threads, cpuCount, dimmCount, gpuCount := int32(16), int32(2), int32(8), int32(4)
+	dimmSize := "32GB"
+	carbideClient := &fakeInstanceTypeClient{
+		instanceType: &bmm.InstanceType{
+			MachineCapabilities: []bmm.MachineCapability{
+				{Type: &cpu, Threads: *bmm.NewNullableInt32(&threads), Count: *bmm.NewNullableInt32(&cpuCount)},
+				{Type: &memory, Capacity: *bmm.NewNullableString(&dimmSize), Count: *bmm.NewNullableInt32(&dimmCount)},
+				{Type: &gpu, Count: *bmm.NewNullableInt32(&gpuCount)},
+			},
+		},
+	}
+
+	machineSet := newTestMachineSet(0)
+	machineSet.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{
+		Raw: []byte(`{"kind":"NvidiaCarbideMachineProviderSpec","siteId":"site",` +
+			`"instanceTypeId":"it-1","credentialsSecret":{"name":"creds"}}`),
+	}
+
+	scheme := runtime.NewScheme()
+	_ = machinev1beta1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machineSet).Build()
+
+	r := &MachineSetCapacityReconciler{
+		Client:   fakeClient,
+		Actuator: machine.NewActuatorWithClient(fakeClient, nil, carbideClient, "test-org"),
+	}
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(machineSet)}
+	for range 2 {
+		result, err := r.Reconcile(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if result.RequeueAfter != DefaultCapacityRefreshInterval {
+			t.Errorf("expected requeue after %v, got %v", DefaultCapacityRefreshInterval, result.RequeueAfter)
+		}
+	}
+
+	if carbideClient.calls != 1 {
+		t.Errorf("expected the instance type to be looked up once, got %d lookups", carbideClient.calls)
+	}
+
+	updated := &machinev1beta1.MachineSet{}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		VCPUAnnotation:    "32",
+		MemoryAnnotation:  "262144",
+		GPUAnnotation:     "4",
+		MaxPodsAnnotation: "250",
+	}
+	for key, value := range want {
+		if got := updated.Annotations[key]; got != value {
+			t.Errorf("expected annotation %s=%s, got %q", key, value, got)
+		}
+	}
+}
+
+func TestMachineSetCapacityReconciler_CachePerCredentials(t *testing.T) {
+	carbideClient := &fakeInstanceTypeClient{instanceType: &bmm.InstanceType{}}
+
+	scheme := runtime.NewScheme()
+	_ = machinev1beta1.AddToScheme(scheme)
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	var requests []ctrl.Request
+	for _, secret := range []string{"creds-a", "creds-b", "creds-a"} {
+		machineSet := newTestMachineSet(0)
+		machineSet.Name = "workers-" + strconv.Itoa(len(requests))
+		machineSet.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{
+			Raw: []byte(`{"kind":"NvidiaCarbideMachineProviderSpec","siteId":"site",` +
+				`"instanceTypeId":"it-1","credentialsSecret":{"name":"` + secret + `"}}`),
+		}
+		builder = builder.WithObjects(machineSet)
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(machineSet)})
+	}
+	fakeClient := builder.Build()
+
+	r := &MachineSetCapacityReconciler{
+		Client:   fakeClient,
+		Actuator: machine.NewActuatorWithClient(fakeClient, nil, carbideClient, "test-org"),
+	}
+	for _, req := range requests {
+		if _, err := r.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	// MachineSets sharing the credentials Secret share the lookup, others do not
+	if carbideClient.calls != 2 {
+		t.Errorf("expected one instance type lookup per credentials Secret, got %d lookups", carbideClient.calls)
+	}
+}
+
+func TestMachineSetCapacityReconciler_ForeignProviderSpec(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{
+			name: "AWS",
+			raw: `{"apiVersion":"machine.openshift.io/v1beta1","kind":"AWSMachineProviderConfig",` +
+				`"instanceType":"m6i.xlarge"}`,
+		},
+		{
+			name: "vSphere",
+			raw: `{"apiVersion":"machine.openshift.io/v1beta1","kind":"VSphereMachineProviderSpec",` +
+				`"numCPUs":4}`,
+		},
+		{
+			name: "own kind in another group",
+			raw:  `{"apiVersion":"example.com/v1","kind":"NvidiaCarbideMachineProviderSpec","instanceTypeId":"it-1"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machineSet := newTestMachineSet(0)
+			machineSet.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{Raw: []byte(tt.raw)}
+
+			scheme := runtime.NewScheme()
+			_ = machinev1beta1.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machineSet).Build()
+			carbideClient := &fakeInstanceTypeClient{}
+			r := &MachineSetCapacityReconciler{
+				Client:   fakeClient,
+				Actuator: machine.NewActuatorWithClient(fakeClient, nil, carbideClient, "test-org"),
+			}
+
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(machineSet)}
+			result, err := r.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if result.RequeueAfter != 0 {
+				t.Errorf("expected no requeue, got %v", result.RequeueAfter)
+			}
+			if carbideClient.calls != 0 {
+				t.Errorf("expected no instance type lookup, got %d", carbideClient.calls)
+			}
+
+			updated := &machinev1beta1.MachineSet{}
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := updated.Annotations[VCPUAnnotation]; ok {
+				t.Error("expected no capacity annotations on a foreign MachineSet")
+			}
+		})
+	}
+}
@@ -22,13 +22,32 @@ import (
 	"time"
 
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+const (
+	// cacheSyncTimeout bounds how long to wait for the cache to observe
+	// Machines created or deleted by the MachineSet controller
+	cacheSyncTimeout = 10 * time.Second
+
+	// cacheSyncInterval is the polling interval while waiting for the cache
+	cacheSyncInterval = 500 * time.Millisecond
+)
+
+// machineSetKind is the GroupVersionKind used in Machine owner references
+var machineSetKind = machinev1beta1.GroupVersion.WithKind("MachineSet")
+
 // MachineSetReconciler reconciles OpenShift MachineSet objects
 type MachineSetReconciler struct {
 	client.Client
@@ -36,8 +55,6 @@ type MachineSetReconciler struct {
 }
 
 // Reconcile handles MachineSet reconciliation to ensure desired replicas
-// Note: This is a simplified implementation. A full implementation would
-// handle replica scaling, machine health checks, and more complex scenarios.
 func (r *MachineSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
@@ -50,8 +67,28 @@ func (r *MachineSetReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// Owned Machines are removed by the garbage collector
+	if !machineSet.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
 	logger.Info("Reconciling OpenShift MachineSet", "machineSet", machineSet.GetName())
 
+	selector, err := metav1.LabelSelectorAsSelector(&machineSet.Spec.Selector)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to parse MachineSet selector: %w", err)
+	}
+	if selector.Empty() {
+		return ctrl.Result{}, fmt.Errorf("MachineSet %s has an empty selector", machineSet.Name)
+	}
+	if !selector.Matches(labels.Set(machineSet.Spec.Template.Labels)) {
+		return ctrl.Result{}, fmt.Errorf("MachineSet %s selector does not match its template labels", machineSet.Name)
+	}
+
+	// Template errors are reported in the status instead of creating
+	// Machines that cannot be provisioned
+	templateErr := validateTemplate(machineSet)
+
 	// Get desired replicas from spec
 	desiredReplicas := int32(0)
 	if machineSet.Spec.Replicas != nil {
@@ -59,27 +96,61 @@ func (r *MachineSetReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	// List current machines owned by this MachineSet
-	// In a full implementation, this would filter by owner reference or labels
-	currentMachines := []runtime.Object{} // Placeholder
+	currentMachines, err := r.getMachinesForMachineSet(ctx, machineSet, selector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 	currentReplicas := int32(len(currentMachines))
 
 	logger.Info("MachineSet status",
 		"desired", desiredReplicas,
 		"current", currentReplicas)
 
+	if err := r.updateStatus(ctx, machineSet, currentMachines, templateErr); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Replace Machines created from an older template, when opted in
+	if rollingUpdateEnabled(machineSet) && templateErr == nil {
+		if err := r.stampProviderSpecHash(ctx, currentMachines); err != nil {
+			return ctrl.Result{}, err
+		}
+		updated, outdated, err := partitionMachines(machineSet, currentMachines)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.updateUpdatedReplicas(ctx, machineSet, len(updated)); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.updateRollingUpdateCondition(ctx, machineSet, len(updated), len(outdated)); err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(outdated) > 0 {
+			logger.Info("Rolling update in progress", "updated", len(updated), "outdated", len(outdated))
+			return r.rollingUpdate(ctx, machineSet, updated, outdated, int(desiredReplicas))
+		}
+	}
+
 	// Scale up if needed
+	if currentReplicas < desiredReplicas && templateErr != nil {
+		logger.Info("Not scaling up, the MachineSet template is invalid", "reason", templateErr.Error())
+		return ctrl.Result{}, nil
+	}
 	if currentReplicas < desiredReplicas {
 		diff := desiredReplicas - currentReplicas
 		logger.Info("Scaling up", "count", diff)
 
+		created := make([]*machinev1beta1.Machine, 0, diff)
 		for i := int32(0); i < diff; i++ {
-			if err := r.createMachine(ctx, machineSet); err != nil {
+			machineObj, err := r.createMachine(ctx, machineSet)
+			if err != nil {
 				logger.Error(err, "failed to create machine")
 				return ctrl.Result{RequeueAfter: 10 * time.Second}, err
 			}
+			created = append(created, machineObj)
 		}
 
-		return ctrl.Result{Requeue: true}, nil
+		return ctrl.Result{}, r.waitForCache(ctx, created, true)
 	}
 
 	// Scale down if needed
@@ -87,45 +158,116 @@ func (r *MachineSetReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		diff := currentReplicas - desiredReplicas
 		logger.Info("Scaling down", "count", diff)
 
-		for i := int32(0); i < diff; i++ {
-			if i < int32(len(currentMachines)) {
-				if err := r.deleteMachine(ctx, currentMachines[i]); err != nil {
-					logger.Error(err, "failed to delete machine")
-					return ctrl.Result{RequeueAfter: 10 * time.Second}, err
-				}
+		toDelete, err := getMachinesToDelete(currentMachines, int(diff), machineSet.Spec.DeletePolicy)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		deleted := make([]*machinev1beta1.Machine, 0, diff)
+		for _, machineObj := range toDelete {
+			if err := r.deleteMachine(ctx, machineObj); err != nil {
+				logger.Error(err, "failed to delete machine")
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, err
 			}
+			deleted = append(deleted, machineObj)
 		}
 
-		return ctrl.Result{Requeue: true}, nil
+		return ctrl.Result{}, r.waitForCache(ctx, deleted, false)
 	}
 
 	logger.Info("MachineSet is at desired replica count")
-	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+
+	// Ready Machines become available once MinReadySeconds have passed
+	if machineSet.Spec.MinReadySeconds > 0 && machineSet.Status.ReadyReplicas != machineSet.Status.AvailableReplicas {
+		return ctrl.Result{RequeueAfter: time.Duration(machineSet.Spec.MinReadySeconds) * time.Second}, nil
+	}
+	return ctrl.Result{}, nil
 }
 
-func (r *MachineSetReconciler) createMachine(ctx context.Context, machineSet *machinev1beta1.MachineSet) error {
+// getMachinesForMachineSet returns the active Machines matching the MachineSet
+// selector that it controls, adopting matching Machines without a controller
+func (r *MachineSetReconciler) getMachinesForMachineSet(
+	ctx context.Context, machineSet *machinev1beta1.MachineSet, selector labels.Selector,
+) ([]*machinev1beta1.Machine, error) {
 	logger := log.FromContext(ctx)
 
-	// In a real implementation, this would:
-	// 1. Clone the machine template from MachineSet.Spec.Template
-	// 2. Set owner reference to the MachineSet
-	// 3. Generate a unique name
-	// 4. Create the Machine resource
+	machineList := &machinev1beta1.MachineList{}
+	if err := r.List(ctx, machineList,
+		client.InNamespace(machineSet.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list machines: %w", err)
+	}
 
-	logger.Info("Creating machine from MachineSet template")
+	machines := make([]*machinev1beta1.Machine, 0, len(machineList.Items))
+	for i := range machineList.Items {
+		machineObj := &machineList.Items[i]
+		if !machineObj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
 
-	// Placeholder - actual implementation would create the Machine
-	return nil
+		controllerRef := metav1.GetControllerOf(machineObj)
+		switch {
+		case controllerRef == nil:
+			logger.Info("Adopting machine", "machine", machineObj.Name)
+			if err := controllerutil.SetControllerReference(machineSet, machineObj, r.Scheme); err != nil {
+				return nil, fmt.Errorf("failed to set controller reference on machine %s: %w", machineObj.Name, err)
+			}
+			if err := r.Update(ctx, machineObj); err != nil {
+				return nil, fmt.Errorf("failed to adopt machine %s: %w", machineObj.Name, err)
+			}
+		case controllerRef.UID != machineSet.UID:
+			// Controlled by another owner
+			continue
+		}
+
+		machines = append(machines, machineObj)
+	}
+
+	return machines, nil
 }
 
-func (r *MachineSetReconciler) deleteMachine(ctx context.Context, machine runtime.Object) error {
+func (r *MachineSetReconciler) createMachine(
+	ctx context.Context, machineSet *machinev1beta1.MachineSet,
+) (*machinev1beta1.Machine, error) {
 	logger := log.FromContext(ctx)
 
-	machineObj, ok := machine.(client.Object)
-	if !ok {
-		return fmt.Errorf("machine is not a client.Object")
+	template := machineSet.Spec.Template.DeepCopy()
+
+	// Record the template the Machine was created from for rolling updates
+	hash, err := providerSpecHash(template.Spec.ProviderSpec.Value)
+	if err != nil {
+		return nil, err
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[ProviderSpecHashAnnotation] = hash
+
+	machineObj := &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: machineSet.Name + "-",
+			Namespace:    machineSet.Namespace,
+			Labels:       template.Labels,
+			Annotations:  template.Annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(machineSet, machineSetKind),
+			},
+		},
+		Spec: template.Spec,
+	}
+
+	if err := r.Create(ctx, machineObj); err != nil {
+		return nil, fmt.Errorf("failed to create machine: %w", err)
 	}
 
+	logger.Info("Created machine from MachineSet template", "machine", machineObj.Name)
+	return machineObj, nil
+}
+
+func (r *MachineSetReconciler) deleteMachine(ctx context.Context, machineObj *machinev1beta1.Machine) error {
+	logger := log.FromContext(ctx)
+
 	logger.Info("Deleting machine", "machine", machineObj.GetName())
 
 	// Delete the Machine resource
@@ -136,9 +278,80 @@ func (r *MachineSetReconciler) deleteMachine(ctx context.Context, machine runtim
 	return nil
 }
 
+// waitForCache waits until the cache reflects the creation or deletion of
+// machines, so the next reconcile does not act on a stale Machine list
+func (r *MachineSetReconciler) waitForCache(
+	ctx context.Context, machines []*machinev1beta1.Machine, created bool,
+) error {
+	for _, machineObj := range machines {
+		key := client.ObjectKeyFromObject(machineObj)
+		err := wait.PollUntilContextTimeout(ctx, cacheSyncInterval, cacheSyncTimeout, true,
+			func(ctx context.Context) (bool, error) {
+				current := &machinev1beta1.Machine{}
+				err := r.Get(ctx, key, current)
+				if errors.IsNotFound(err) {
+					return !created, nil
+				}
+				if err != nil {
+					return false, err
+				}
+				return created || !current.GetDeletionTimestamp().IsZero(), nil
+			})
+		if err != nil {
+			return fmt.Errorf("failed waiting for cache to observe machine %s: %w", key.Name, err)
+		}
+	}
+	return nil
+}
+
+// updateStatus reports the replica counts and template errors
+func (r *MachineSetReconciler) updateStatus(
+	ctx context.Context, machineSet *machinev1beta1.MachineSet, machines []*machinev1beta1.Machine, templateErr error,
+) error {
+	newStatus, err := r.calculateStatus(ctx, machineSet, machines, templateErr)
+	if err != nil {
+		return err
+	}
+
+	if equalStatus(machineSet.Status, newStatus) {
+		return nil
+	}
+
+	// A merge patch keeps the status conditions, which the MachineSet type
+	// does not know
+	patchBase := client.MergeFrom(machineSet.DeepCopy())
+	machineSet.Status = newStatus
+	if err := r.Status().Patch(ctx, machineSet, patchBase); err != nil {
+		return fmt.Errorf("failed to update MachineSet status: %w", err)
+	}
+	return nil
+}
+
+func equalStatus(a, b machinev1beta1.MachineSetStatus) bool {
+	return a.Replicas == b.Replicas &&
+		a.FullyLabeledReplicas == b.FullyLabeledReplicas &&
+		a.ReadyReplicas == b.ReadyReplicas &&
+		a.AvailableReplicas == b.AvailableReplicas &&
+		a.ObservedGeneration == b.ObservedGeneration &&
+		equality.Semantic.DeepEqual(a.ErrorReason, b.ErrorReason) &&
+		equality.Semantic.DeepEqual(a.ErrorMessage, b.ErrorMessage)
+}
+
 // SetupWithManager sets up the controller with the Manager
 func (r *MachineSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&machinev1beta1.MachineSet{}).
+		Owns(&machinev1beta1.Machine{}).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.nodeToMachineSet)).
 		Complete(r)
 }
+
+// SetupMachineSetController creates and registers the MachineSet controller with the manager
+func SetupMachineSetController(mgr ctrl.Manager) error {
+	reconciler := &MachineSetReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+
+	return reconciler.SetupWithManager(mgr)
+}
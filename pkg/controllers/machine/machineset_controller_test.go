@@ -0,0 +1,621 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const testMachineSetLabel = "machine.openshift.io/cluster-api-machineset"
+
+func newTestMachineSet(replicas int32) *machinev1beta1.MachineSet {
+	return &machinev1beta1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "workers",
+			Namespace:  "openshift-machine-api",
+			UID:        types.UID("machineset-uid"),
+			Generation: 2,
+		},
+		Spec: machinev1beta1.MachineSetSpec{
+			Replicas: &replicas,
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{testMachineSetLabel: "workers"},
+			},
+			Template: machinev1beta1.MachineTemplateSpec{
+				ObjectMeta: machinev1beta1.ObjectMeta{
+					Labels: map[string]string{testMachineSetLabel: "workers"},
+				},
+			},
+		},
+	}
+}
+
+func newTestMachineSetReconciler(objs ...client.Object) *MachineSetReconciler {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = machinev1beta1.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&machinev1beta1.MachineSet{}).
+		Build()
+
+	return &MachineSetReconciler{Client: fakeClient, Scheme: scheme}
+}
+
+func reconcileMachineSet(t *testing.T, r *MachineSetReconciler, machineSet *machinev1beta1.MachineSet) {
+	t.Helper()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(machineSet)}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+}
+
+func listTestMachines(t *testing.T, r *MachineSetReconciler) []machinev1beta1.Machine {
+	t.Helper()
+	machineList := &machinev1beta1.MachineList{}
+	if err := r.List(context.Background(), machineList); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	return machineList.Items
+}
+
+func TestMachineSetReconciler_ScaleUpAndDown(t *testing.T) {
+	machineSet := newTestMachineSet(3)
+	r := newTestMachineSetReconciler(machineSet)
+
+	reconcileMachineSet(t, r, machineSet)
+
+	machines := listTestMachines(t, r)
+	if len(machines) != 3 {
+		t.Fatalf("expected 3 machines after scale up, got %d", len(machines))
+	}
+	for _, m := range machines {
+		ref := metav1.GetControllerOf(&m)
+		if ref == nil || ref.UID != machineSet.UID {
+			t.Errorf("machine %s is not controlled by the MachineSet", m.Name)
+		}
+		if m.Labels[testMachineSetLabel] != "workers" {
+			t.Errorf("machine %s is missing template labels", m.Name)
+		}
+	}
+
+	// A second reconcile reports the replicas
+	reconcileMachineSet(t, r, machineSet)
+	updated := &machinev1beta1.MachineSet{}
+	if err := r.Get(context.Background(), client.ObjectKeyFromObject(machineSet), updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.Replicas != 3 {
+		t.Errorf("expected status.replicas=3, got %d", updated.Status.Replicas)
+	}
+	if updated.Status.ObservedGeneration != machineSet.Generation {
+		t.Errorf("expected observedGeneration=%d, got %d", machineSet.Generation, updated.Status.ObservedGeneration)
+	}
+
+	// Scale down
+	replicas := int32(1)
+	updated.Spec.Replicas = &replicas
+	if err := r.Update(context.Background(), updated); err != nil {
+		t.Fatal(err)
+	}
+	reconcileMachineSet(t, r, machineSet)
+
+	if machines := listTestMachines(t, r); len(machines) != 1 {
+		t.Errorf("expected 1 machine after scale down, got %d", len(machines))
+	}
+}
+
+func TestMachineSetReconciler_IgnoresMachinesOwnedByOthers(t *testing.T) {
+	machineSet := newTestMachineSet(1)
+	isController := true
+	foreign := &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foreign",
+			Namespace: machineSet.Namespace,
+			Labels:    map[string]string{testMachineSetLabel: "workers"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: machinev1beta1.GroupVersion.String(),
+				Kind:       "MachineSet",
+				Name:       "other",
+				UID:        types.UID("other-uid"),
+				Controller: &isController,
+			}},
+		},
+	}
+	r := newTestMachineSetReconciler(machineSet, foreign)
+
+	reconcileMachineSet(t, r, machineSet)
+
+	if machines := listTestMachines(t, r); len(machines) != 2 {
+		t.Errorf("expected the foreign machine plus 1 new machine, got %d", len(machines))
+	}
+}
+
+func TestMachineSetReconciler_Status(t *testing.T) {
+	machineSet := newTestMachineSet(3)
+	machineSet.Spec.MinReadySeconds = 300
+
+	newNode := func(name string, ready corev1.ConditionStatus, since time.Duration) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+				Type:               corev1.NodeReady,
+				Status:             ready,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+			}}},
+		}
+	}
+	newMachine := func(name, nodeName string, machineLabels map[string]string) *machinev1beta1.Machine {
+		m := &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       machineSet.Namespace,
+				Labels:          machineLabels,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(machineSet, machineSetKind)},
+			},
+		}
+		if nodeName != "" {
+			m.Status.NodeRef = &corev1.ObjectReference{Name: nodeName}
+		}
+		return m
+	}
+	extraLabels := map[string]string{testMachineSetLabel: "workers", "extra": "label"}
+
+	r := newTestMachineSetReconciler(
+		machineSet,
+		// Ready for longer than MinReadySeconds
+		newMachine("available", "node-available", extraLabels),
+		newNode("node-available", corev1.ConditionTrue, time.Hour),
+		// Ready, but not for MinReadySeconds yet
+		newMachine("ready", "node-ready", extraLabels),
+		newNode("node-ready", corev1.ConditionTrue, time.Minute),
+		// Node not ready, and missing a template label
+		newMachine("not-ready", "node-not-ready", map[string]string{testMachineSetLabel: "workers", "other": "x"}),
+		newNode("node-not-ready", corev1.ConditionFalse, time.Hour),
+	)
+	// The template carries a label the last machine lacks
+	machineSet.Spec.Template.Labels = map[string]string{testMachineSetLabel: "workers", "extra": "label"}
+	if err := r.Update(context.Background(), machineSet); err != nil {
+		t.Fatal(err)
+	}
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(machineSet)}
+	result, err := r.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != 300*time.Second {
+		t.Errorf("expected requeue after MinReadySeconds, got %v", result.RequeueAfter)
+	}
+
+	updated := &machinev1beta1.MachineSet{}
+	if err := r.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatal(err)
+	}
+	status := updated.Status
+	if status.Replicas != 3 || status.FullyLabeledReplicas != 2 ||
+		status.ReadyReplicas != 2 || status.AvailableReplicas != 1 {
+		t.Errorf("expected replicas=3 fullyLabeled=2 ready=2 available=1, got %+v", status)
+	}
+	if status.ErrorReason != nil {
+		t.Errorf("expected no error reason, got %v", *status.ErrorReason)
+	}
+}
+
+func TestMachineSetReconciler_InvalidTemplate(t *testing.T) {
+	machineSet := newTestMachineSet(2)
+	machineSet.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{Raw: []byte(`{"siteId": 42}`)}
+	r := newTestMachineSetReconciler(machineSet)
+
+	reconcileMachineSet(t, r, machineSet)
+
+	if machines := listTestMachines(t, r); len(machines) != 0 {
+		t.Errorf("expected no machines from an invalid template, got %d", len(machines))
+	}
+
+	updated := &machinev1beta1.MachineSet{}
+	if err := r.Get(context.Background(), client.ObjectKeyFromObject(machineSet), updated); err != nil {
+		t.Fatal(err)
+	}
+	wantReason := machinev1beta1.InvalidConfigurationMachineSetError
+	if updated.Status.ErrorReason == nil || *updated.Status.ErrorReason != wantReason {
+		t.Errorf("expected error reason %s, got %v", wantReason, updated.Status.ErrorReason)
+	}
+	if updated.Status.ErrorMessage == nil {
+		t.Error("expected an error message")
+	}
+}
+
+func TestGetMachinesToDelete(t *testing.T) {
+	now := time.Now()
+	failed := machinev1beta1.PhaseFailed
+	nodeRef := &corev1.ObjectReference{Name: "node"}
+
+	newMachine := func(name string, age time.Duration, mutate func(*machinev1beta1.Machine)) *machinev1beta1.Machine {
+		m := &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Status: machinev1beta1.MachineStatus{NodeRef: nodeRef},
+		}
+		if mutate != nil {
+			mutate(m)
+		}
+		return m
+	}
+
+	oldest := newMachine("oldest", 3*time.Hour, nil)
+	middle := newMachine("middle", 2*time.Hour, nil)
+	newest := newMachine("newest", time.Hour, nil)
+	annotated := newMachine("annotated", 2*time.Hour, func(m *machinev1beta1.Machine) {
+		m.Annotations = map[string]string{DeleteMachineAnnotation: "true"}
+	})
+	failedMachine := newMachine("failed", 2*time.Hour, func(m *machinev1beta1.Machine) {
+		m.Status.Phase = &failed
+	})
+	noNode := newMachine("no-node", 2*time.Hour, func(m *machinev1beta1.Machine) {
+		m.Status.NodeRef = nil
+	})
+
+	tests := []struct {
+		name     string
+		machines []*machinev1beta1.Machine
+		count    int
+		policy   string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "oldest policy",
+			machines: []*machinev1beta1.Machine{middle, newest, oldest},
+			count:    2,
+			policy:   string(machinev1beta1.OldestMachineSetDeletePolicy),
+			want:     []string{"oldest", "middle"},
+		},
+		{
+			name:     "newest policy",
+			machines: []*machinev1beta1.Machine{middle, oldest, newest},
+			count:    2,
+			policy:   string(machinev1beta1.NewestMachineSetDeletePolicy),
+			want:     []string{"newest", "middle"},
+		},
+		{
+			name:     "annotated then unhealthy before policy order",
+			machines: []*machinev1beta1.Machine{oldest, noNode, newest, annotated},
+			count:    3,
+			policy:   string(machinev1beta1.NewestMachineSetDeletePolicy),
+			want:     []string{"annotated", "no-node", "newest"},
+		},
+		{
+			name:     "random policy still prefers annotated and failed machines",
+			machines: []*machinev1beta1.Machine{oldest, failedMachine, newest, annotated},
+			count:    2,
+			policy:   string(machinev1beta1.RandomMachineSetDeletePolicy),
+			want:     []string{"annotated", "failed"},
+		},
+		{
+			name:     "unknown policy",
+			machines: []*machinev1beta1.Machine{oldest},
+			count:    1,
+			policy:   "Cheapest",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getMachinesToDelete(tt.machines, tt.count, tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getMachinesToDelete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			names := make([]string, 0, len(got))
+			for _, m := range got {
+				names = append(names, m.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, names)
+			}
+		})
+	}
+}
+
+func TestMachineSetReconciler_RollingUpdate(t *testing.T) {
+	ctx := context.Background()
+	machineSet := newTestMachineSet(2)
+	machineSet.Annotations = map[string]string{RollingUpdateAnnotation: "true"}
+	machineSet.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{
+		Raw: []byte(`{"siteId":"site","instanceTypeId":"new-type"}`),
+	}
+	templateHash, err := providerSpecHash(machineSet.Spec.Template.Spec.ProviderSpec.Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readyNode := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+				Type:   corev1.NodeReady,
+				Status: corev1.ConditionTrue,
+			}}},
+		}
+	}
+	outdatedMachine := func(name string) *machinev1beta1.Machine {
+		return &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       machineSet.Namespace,
+				Labels:          map[string]string{testMachineSetLabel: "workers"},
+				Annotations:     map[string]string{ProviderSpecHashAnnotation: "old-hash"},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(machineSet, machineSetKind)},
+			},
+			Spec: machinev1beta1.MachineSpec{ProviderSpec: machinev1beta1.ProviderSpec{
+				Value: &runtime.RawExtension{Raw: []byte(`{"instanceTypeId":"old-type","siteId":"site"}`)},
+			}},
+			Status: machinev1beta1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: name}},
+		}
+	}
+
+	r := newTestMachineSetReconciler(
+		machineSet,
+		outdatedMachine("old-1"), readyNode("old-1"),
+		outdatedMachine("old-2"), readyNode("old-2"),
+	)
+
+	countMachines := func() (updated, outdated []machinev1beta1.Machine) {
+		t.Helper()
+		for _, m := range listTestMachines(t, r) {
+			if m.Annotations[ProviderSpecHashAnnotation] == templateHash {
+				updated = append(updated, m)
+			} else {
+				outdated = append(outdated, m)
+			}
+		}
+		return updated, outdated
+	}
+
+	// The first pass surges one replacement and keeps both outdated Machines
+	reconcileMachineSet(t, r, machineSet)
+	updated, outdated := countMachines()
+	if len(updated) != 1 || len(outdated) != 2 {
+		t.Fatalf("expected 1 updated and 2 outdated machines, got %d and %d", len(updated), len(outdated))
+	}
+
+	// Nothing is deleted until the replacement has a Ready Node
+	reconcileMachineSet(t, r, machineSet)
+	if _, outdated := countMachines(); len(outdated) != 2 {
+		t.Fatalf("expected outdated machines to be kept, got %d", len(outdated))
+	}
+
+	replacement := updated[0]
+	replacement.Status.NodeRef = &corev1.ObjectReference{Name: replacement.Name}
+	if err := r.Update(ctx, &replacement); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Create(ctx, readyNode(replacement.Name)); err != nil {
+		t.Fatal(err)
+	}
+
+	// One outdated Machine is deleted, then a second replacement is surged
+	reconcileMachineSet(t, r, machineSet)
+	reconcileMachineSet(t, r, machineSet)
+	updated, outdated = countMachines()
+	if len(updated) != 2 || len(outdated) != 1 {
+		t.Fatalf("expected 2 updated and 1 outdated machines, got %d and %d", len(updated), len(outdated))
+	}
+
+	current := &machinev1beta1.MachineSet{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(machineSet), current); err != nil {
+		t.Fatal(err)
+	}
+	if got := current.Annotations[UpdatedReplicasAnnotation]; got != "1" {
+		t.Errorf("expected %s=1, got %q", UpdatedReplicasAnnotation, got)
+	}
+}
+
+func TestMachineSetReconciler_RollingUpdateStampsExistingMachines(t *testing.T) {
+	machineSet := newTestMachineSet(2)
+	machineSet.Annotations = map[string]string{RollingUpdateAnnotation: "true"}
+	machineSet.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{
+		Raw: []byte(`{"siteId":"site","instanceTypeId":"new-type"}`),
+	}
+	templateHash, err := providerSpecHash(machineSet.Spec.Template.Spec.ProviderSpec.Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Machines created before rolling updates were enabled, one from an older
+	// template and one matching the current template
+	existingSpecs := map[string]string{
+		"existing-old": `{"instanceTypeId":"old-type","siteId":"site"}`,
+		"existing-new": `{"instanceTypeId":"new-type","siteId":"site"}`,
+	}
+	var objs []client.Object
+	objs = append(objs, machineSet)
+	for name, spec := range existingSpecs {
+		objs = append(objs, &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       machineSet.Namespace,
+				Labels:          map[string]string{testMachineSetLabel: "workers"},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(machineSet, machineSetKind)},
+			},
+			Spec: machinev1beta1.MachineSpec{ProviderSpec: machinev1beta1.ProviderSpec{
+				Value: &runtime.RawExtension{Raw: []byte(spec)},
+			}},
+		})
+	}
+	r := newTestMachineSetReconciler(objs...)
+
+	reconcileMachineSet(t, r, machineSet)
+	replacements := 0
+	for _, m := range listTestMachines(t, r) {
+		spec, existing := existingSpecs[m.Name]
+		if !existing {
+			replacements++
+			continue
+		}
+		wantHash, err := providerSpecHash(&runtime.RawExtension{Raw: []byte(spec)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := m.Annotations[ProviderSpecHashAnnotation]; got != wantHash {
+			t.Errorf("machine %s: expected %s=%s, got %q", m.Name, ProviderSpecHashAnnotation, wantHash, got)
+		}
+		if m.Name == "existing-new" && wantHash != templateHash {
+			t.Errorf("expected the machine matching the template to be up to date")
+		}
+	}
+
+	// Only the Machine from the older template is replaced
+	if replacements != 1 {
+		t.Errorf("expected 1 replacement machine, got %d", replacements)
+	}
+}
+
+func TestMachineSetReconciler_RollingUpdateCondition(t *testing.T) {
+	machineSet := newTestMachineSet(1)
+	machineSet.Annotations = map[string]string{RollingUpdateAnnotation: "true"}
+	machineSet.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{
+		Raw: []byte(`{"siteId":"site","instanceTypeId":"new-type"}`),
+	}
+	outdated := &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "old-1",
+			Namespace:       machineSet.Namespace,
+			Labels:          map[string]string{testMachineSetLabel: "workers"},
+			Annotations:     map[string]string{ProviderSpecHashAnnotation: "old-hash"},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(machineSet, machineSetKind)},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = machinev1beta1.AddToScheme(scheme)
+
+	// The MachineSet type has no conditions, so the status patches are
+	// captured instead of read back
+	var conditions []interface{}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(machineSet, outdated).
+		WithStatusSubresource(&machinev1beta1.MachineSet{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string,
+				obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				if u, ok := obj.(*unstructured.Unstructured); ok {
+					conditions, _, _ = unstructured.NestedSlice(u.Object, "status", "conditions")
+				}
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+	r := &MachineSetReconciler{Client: fakeClient, Scheme: scheme}
+
+	reconcileMachineSet(t, r, machineSet)
+
+	if len(conditions) != 1 {
+		t.Fatalf("expected 1 condition, got %v", conditions)
+	}
+	condition := conditions[0].(map[string]interface{})
+	if condition["type"] != string(MachinesUpdatedCondition) ||
+		condition["status"] != string(corev1.ConditionFalse) ||
+		condition["reason"] != RollingUpdateInProgressReason ||
+		condition["message"] != "0 machines updated, 1 outdated" {
+		t.Errorf("unexpected condition %v", condition)
+	}
+}
+
+func TestMachineSetReconciler_RollingUpdateNotReadyMachines(t *testing.T) {
+	machineSet := newTestMachineSet(2)
+	machineSet.Annotations = map[string]string{RollingUpdateAnnotation: "true"}
+	machineSet.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{
+		Raw: []byte(`{"siteId":"site","instanceTypeId":"new-type"}`),
+	}
+
+	var objs []client.Object
+	objs = append(objs, machineSet)
+	for _, name := range []string{"old-1", "old-2"} {
+		objs = append(objs, &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       machineSet.Namespace,
+				Labels:          map[string]string{testMachineSetLabel: "workers"},
+				Annotations:     map[string]string{ProviderSpecHashAnnotation: "old-hash"},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(machineSet, machineSetKind)},
+			},
+		})
+	}
+	r := newTestMachineSetReconciler(objs...)
+
+	// Outdated Machines without a Ready Node count against maxUnavailable, so
+	// they are kept while the replacement is not Ready either
+	reconcileMachineSet(t, r, machineSet)
+	reconcileMachineSet(t, r, machineSet)
+	outdated := 0
+	machines := listTestMachines(t, r)
+	for _, m := range machines {
+		if m.Annotations[ProviderSpecHashAnnotation] == "old-hash" {
+			outdated++
+		}
+	}
+	if len(machines) != 3 || outdated != 2 {
+		t.Errorf("expected 3 machines with 2 outdated, got %d with %d outdated", len(machines), outdated)
+	}
+}
+
+func TestProviderSpecHash(t *testing.T) {
+	a, err := providerSpecHash(&runtime.RawExtension{Raw: []byte(`{"siteId":"a","vpcId":"b"}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := providerSpecHash(&runtime.RawExtension{Raw: []byte(`{"vpcId": "b", "siteId": "a"}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf("expected the hash to ignore field order, got %s and %s", a, b)
+	}
+
+	c, err := providerSpecHash(&runtime.RawExtension{Raw: []byte(`{"siteId":"a","vpcId":"c"}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == c {
+		t.Error("expected different provider specs to have different hashes")
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+)
+
+// validateTemplate checks that the MachineSet template provider spec can be decoded
+func validateTemplate(machineSet *machinev1beta1.MachineSet) error {
+	providerSpecValue := machineSet.Spec.Template.Spec.ProviderSpec.Value
+	own, err := machine.IsOwnProviderSpec(providerSpecValue)
+	if err != nil {
+		return fmt.Errorf("invalid template provider spec: %w", err)
+	}
+	if !own {
+		return nil
+	}
+	if _, err := machine.ProviderSpecFromRawExtension(providerSpecValue); err != nil {
+		return fmt.Errorf("invalid template provider spec: %w", err)
+	}
+	return nil
+}
+
+// calculateStatus computes the MachineSet status from its Machines and their Nodes.
+// A Machine is ready when its Node is Ready, and available once its Node has
+// been Ready for MinReadySeconds.
+func (r *MachineSetReconciler) calculateStatus(
+	ctx context.Context, machineSet *machinev1beta1.MachineSet, machines []*machinev1beta1.Machine, templateErr error,
+) (machinev1beta1.MachineSetStatus, error) {
+	newStatus := *machineSet.Status.DeepCopy()
+	newStatus.Replicas = int32(len(machines))
+	newStatus.FullyLabeledReplicas = 0
+	newStatus.ReadyReplicas = 0
+	newStatus.AvailableReplicas = 0
+	newStatus.ObservedGeneration = machineSet.Generation
+
+	templateLabels := labels.Set(machineSet.Spec.Template.Labels).AsSelectorPreValidated()
+	minReady := time.Duration(machineSet.Spec.MinReadySeconds) * time.Second
+	now := time.Now()
+
+	for _, machineObj := range machines {
+		if templateLabels.Matches(labels.Set(machineObj.Labels)) {
+			newStatus.FullyLabeledReplicas++
+		}
+
+		readySince, err := r.nodeReadySince(ctx, machineObj)
+		if err != nil {
+			return newStatus, err
+		}
+		if readySince == nil {
+			continue
+		}
+		newStatus.ReadyReplicas++
+		if minReady == 0 || !readySince.Add(minReady).After(now) {
+			newStatus.AvailableReplicas++
+		}
+	}
+
+	if templateErr != nil {
+		reason := machinev1beta1.InvalidConfigurationMachineSetError
+		message := templateErr.Error()
+		newStatus.ErrorReason = &reason
+		newStatus.ErrorMessage = &message
+	} else {
+		newStatus.ErrorReason = nil
+		newStatus.ErrorMessage = nil
+	}
+
+	return newStatus, nil
+}
+
+// nodeReadySince returns when the Node of a Machine became Ready, or nil when
+// the Machine has no Node or the Node is not Ready
+func (r *MachineSetReconciler) nodeReadySince(
+	ctx context.Context, machineObj *machinev1beta1.Machine,
+) (*time.Time, error) {
+	if machineObj.Status.NodeRef == nil {
+		return nil, nil
+	}
+
+	node := &corev1.Node{}
+	if err := r.Get(ctx, client.ObjectKey{Name: machineObj.Status.NodeRef.Name}, node); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get node %s: %w", machineObj.Status.NodeRef.Name, err)
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+			return &condition.LastTransitionTime.Time, nil
+		}
+	}
+	return nil, nil
+}
+
+// nodeToMachineSet maps a Node to the MachineSet controlling its Machine, so
+// that Node readiness changes refresh the ready and available counts
+func (r *MachineSetReconciler) nodeToMachineSet(ctx context.Context, obj client.Object) []reconcile.Request {
+	machineList := &machinev1beta1.MachineList{}
+	if err := r.List(ctx, machineList); err != nil {
+		return nil
+	}
+
+	for _, machineObj := range machineList.Items {
+		if machineObj.Status.NodeRef == nil || machineObj.Status.NodeRef.Name != obj.GetName() {
+			continue
+		}
+		for _, ref := range machineObj.OwnerReferences {
+			if ref.Controller != nil && *ref.Controller && ref.Kind == machineSetKind.Kind {
+				return []reconcile.Request{{NamespacedName: client.ObjectKey{
+					Namespace: machineObj.Namespace,
+					Name:      ref.Name,
+				}}}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/metrics"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/providerid"
+)
+
+const (
+	// ProviderIDMismatchAnnotation is set on a Node whose provider ID is still in
+	// the legacy format. The Node provider ID is immutable, so the Node must
+	// register again for its Machine to be migrated. Its value is the provider
+	// ID the Node should register with.
+	ProviderIDMismatchAnnotation = "nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/provider-id-mismatch"
+
+	// ProviderIDMigratedReason is the event reason of a migrated Machine
+	ProviderIDMigratedReason = "ProviderIDMigrated"
+
+	// ProviderIDMigrationFailedReason is the event reason of a Machine whose
+	// provider ID cannot be migrated
+	ProviderIDMigrationFailedReason = "ProviderIDMigrationFailed"
+
+	// ProviderIDMismatchReason is the event reason of a flagged Node
+	ProviderIDMismatchReason = "ProviderIDMismatch"
+)
+
+// ProviderIDMigrationReconciler rewrites legacy nvidia-carbide://org/site/id
+// Machine provider IDs to the tenant-aware format.
+//
+// Nodelink, MachineHealthChecks and the cluster-autoscaler match a Machine to
+// its Node by provider ID, and the Node provider ID is immutable. A Machine is
+// therefore only migrated when no Node carries its legacy provider ID, that
+// is before its Node registers or once the Node registered again with the new
+// provider ID. Until then the Node is flagged. Nodes are watched, so that the
+// Machine is migrated as soon as its Node registered again.
+type ProviderIDMigrationReconciler struct {
+	client.Client
+	EventRecorder record.EventRecorder
+}
+
+// Reconcile migrates the provider ID of a Machine, or flags its Node while
+// the Node still has the legacy provider ID
+func (r *ProviderIDMigrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	machineObj := &machinev1beta1.Machine{}
+	if err := r.Get(ctx, req.NamespacedName, machineObj); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if machineObj.Spec.ProviderID == nil || !machineObj.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+	pid, err := providerid.ParseProviderID(*machineObj.Spec.ProviderID)
+	if err != nil || !pid.IsLegacy() {
+		// Not ours, or already migrated
+		return ctrl.Result{}, nil
+	}
+
+	migrated, err := tenantAwareProviderID(machineObj, pid)
+	if err != nil {
+		// Retried when the provider spec changes
+		metrics.RecordProviderIDMigration(err)
+		r.event(machineObj, corev1.EventTypeWarning, ProviderIDMigrationFailedReason, err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := r.List(ctx, nodeList); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	if node := nodeWithProviderID(nodeList.Items, *machineObj.Spec.ProviderID); node != nil {
+		return ctrl.Result{}, r.flagNode(ctx, node, machineObj, migrated)
+	}
+
+	patchBase := client.MergeFrom(machineObj.DeepCopy())
+	machineObj.Spec.ProviderID = &migrated
+	err = r.Patch(ctx, machineObj, patchBase)
+	metrics.RecordProviderIDMigration(err)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update provider ID: %w", err)
+	}
+	logger.Info("Migrated legacy provider ID", "providerID", migrated)
+	r.event(machineObj, corev1.EventTypeNormal, ProviderIDMigratedReason,
+		fmt.Sprintf("Migrated provider ID to %s", migrated))
+	return ctrl.Result{}, nil
+}
+
+// tenantAwareProviderID returns the Machine provider ID with the tenant of its
+// provider spec
+func tenantAwareProviderID(machineObj *machinev1beta1.Machine, pid *providerid.ProviderID) (string, error) {
+	providerSpec, err := machine.ProviderSpecFromRawExtension(machineObj.Spec.ProviderSpec.Value)
+	if err != nil {
+		return "", fmt.Errorf("failed to get provider spec: %w", err)
+	}
+	if providerSpec.TenantID == "" {
+		return "", fmt.Errorf("cannot migrate provider ID %s: providerSpec.tenantId is not set",
+			*machineObj.Spec.ProviderID)
+	}
+
+	migratedID, err := providerid.NewProviderID(pid.OrgName, providerSpec.TenantID, pid.SiteName, pid.InstanceID)
+	if err != nil {
+		return "", err
+	}
+	return migratedID.String(), nil
+}
+
+// flagNode annotates a Node still registered with the legacy provider ID of
+// its Machine with the provider ID it should register with
+func (r *ProviderIDMigrationReconciler) flagNode(
+	ctx context.Context, node *corev1.Node, machineObj *machinev1beta1.Machine, migrated string,
+) error {
+	if node.Annotations[ProviderIDMismatchAnnotation] == migrated {
+		return nil
+	}
+
+	patchBase := client.MergeFrom(node.DeepCopy())
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[ProviderIDMismatchAnnotation] = migrated
+	if err := r.Patch(ctx, node, patchBase); err != nil {
+		return fmt.Errorf("failed to patch node: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Keeping legacy provider ID until the Node registers again",
+		"node", node.Name, "nodeProviderID", node.Spec.ProviderID, "providerID", migrated)
+	r.event(node, corev1.EventTypeWarning, ProviderIDMismatchReason,
+		fmt.Sprintf("Node provider ID %s is in the legacy format, recreate the Node with provider ID %s "+
+			"to migrate Machine %s/%s", node.Spec.ProviderID, migrated, machineObj.Namespace, machineObj.Name))
+	return nil
+}
+
+func (r *ProviderIDMigrationReconciler) event(obj client.Object, eventType, reason, message string) {
+	if r.EventRecorder != nil {
+		r.EventRecorder.Event(obj, eventType, reason, message)
+	}
+}
+
+// nodeWithProviderID returns the Node with a provider ID, compared by value,
+// or nil
+func nodeWithProviderID(nodes []corev1.Node, providerID string) *corev1.Node {
+	for i := range nodes {
+		if nodes[i].Spec.ProviderID != "" && providerIDsEqual(nodes[i].Spec.ProviderID, providerID) {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
+
+// nodeToLegacyMachines maps a Node to the Machines with a legacy provider ID
+// of the same instance, so that they are migrated once the Node registered
+// again
+func (r *ProviderIDMigrationReconciler) nodeToLegacyMachines(ctx context.Context, obj client.Object) []reconcile.Request {
+	node, ok := obj.(*corev1.Node)
+	if !ok || node.Spec.ProviderID == "" {
+		return nil
+	}
+	nodePID, err := providerid.ParseProviderID(node.Spec.ProviderID)
+	if err != nil {
+		return nil
+	}
+
+	machineList := &machinev1beta1.MachineList{}
+	if err := r.List(ctx, machineList); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range machineList.Items {
+		machineObj := &machineList.Items[i]
+		if machineObj.Spec.ProviderID == nil {
+			continue
+		}
+		pid, err := providerid.ParseProviderID(*machineObj.Spec.ProviderID)
+		if err != nil || !pid.IsLegacy() || pid.InstanceID != nodePID.InstanceID {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(machineObj)})
+	}
+	return requests
+}
+
+// providerIDsEqual compares provider IDs by value when both parse
+func providerIDsEqual(a, b string) bool {
+	pidA, errA := providerid.ParseProviderID(a)
+	pidB, errB := providerid.ParseProviderID(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return pidA.Equal(pidB)
+}
+
+// hasLegacyProviderID filters out Machines without a legacy provider ID of
+// this provider
+func hasLegacyProviderID(obj client.Object) bool {
+	machineObj, ok := obj.(*machinev1beta1.Machine)
+	if !ok || machineObj.Spec.ProviderID == nil {
+		return false
+	}
+	pid, err := providerid.ParseProviderID(*machineObj.Spec.ProviderID)
+	return err == nil && pid.IsLegacy()
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *ProviderIDMigrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("providerid-migration").
+		For(&machinev1beta1.Machine{}, builder.WithPredicates(predicate.NewPredicateFuncs(hasLegacyProviderID))).
+		// The Node provider ID is immutable, Nodes only matter when they register
+		// or go away
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.nodeToLegacyMachines),
+			builder.WithPredicates(predicate.Funcs{
+				UpdateFunc:  func(event.UpdateEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			})).
+		Complete(r)
+}
+
+// SetupProviderIDMigrationController creates and registers the provider ID
+// migration controller with the manager
+func SetupProviderIDMigrationController(mgr ctrl.Manager) error {
+	reconciler := &ProviderIDMigrationReconciler{
+		Client:        mgr.GetClient(),
+		EventRecorder: mgr.GetEventRecorderFor("nvidia-carbide-providerid-migration"),
+	}
+
+	return reconciler.SetupWithManager(mgr)
+}
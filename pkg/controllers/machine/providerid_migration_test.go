@@ -0,0 +1,204 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testInstanceID     = "6f1c6f3e-8c7a-4d8e-9a2b-0c4f2d6e8a10"
+	legacyProviderID   = "nvidia-carbide://test-org/site-a/" + testInstanceID
+	migratedProviderID = "nvidia-carbide://test-org/tenant-a/site-a/" + testInstanceID
+)
+
+func newMigrationMachine(providerID, tenantID string) *machinev1beta1.Machine {
+	return &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: "openshift-machine-api"},
+		Spec: machinev1beta1.MachineSpec{
+			ProviderID: &providerID,
+			ProviderSpec: machinev1beta1.ProviderSpec{
+				Value: &runtime.RawExtension{
+					Raw: []byte(`{"apiVersion":"nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/v1",` +
+						`"kind":"NvidiaCarbideMachineProviderSpec","siteId":"site-a","tenantId":"` + tenantID + `"}`),
+				},
+			},
+		},
+		Status: machinev1beta1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: "worker-0"}},
+	}
+}
+
+func TestProviderIDMigrationReconciler(t *testing.T) {
+	tests := []struct {
+		name           string
+		machine        *machinev1beta1.Machine
+		nodeProviderID string
+		wantProviderID string
+		wantEvents     int
+		wantMismatch   string
+	}{
+		{
+			name:           "legacy provider ID of a Machine without Node is migrated",
+			machine:        newMigrationMachine(legacyProviderID, "tenant-a"),
+			wantProviderID: migratedProviderID,
+			wantEvents:     1,
+		},
+		{
+			name:           "legacy provider ID is migrated once the Node registered with the new one",
+			machine:        newMigrationMachine(legacyProviderID, "tenant-a"),
+			nodeProviderID: migratedProviderID,
+			wantProviderID: migratedProviderID,
+			wantEvents:     1,
+		},
+		{
+			name:           "legacy provider ID still used by the Node is kept and the Node flagged",
+			machine:        newMigrationMachine(legacyProviderID, "tenant-a"),
+			nodeProviderID: legacyProviderID,
+			wantProviderID: legacyProviderID,
+			wantEvents:     1,
+			wantMismatch:   migratedProviderID,
+		},
+		{
+			name:           "legacy provider ID without tenant is left as is",
+			machine:        newMigrationMachine(legacyProviderID, ""),
+			nodeProviderID: legacyProviderID,
+			wantProviderID: legacyProviderID,
+			wantEvents:     1,
+		},
+		{
+			name:           "migrated provider ID is untouched",
+			machine:        newMigrationMachine(migratedProviderID, "tenant-a"),
+			nodeProviderID: migratedProviderID,
+			wantProviderID: migratedProviderID,
+		},
+		{
+			name:           "foreign provider ID is ignored",
+			machine:        newMigrationMachine("aws:///us-east-1a/i-0123456789", "tenant-a"),
+			nodeProviderID: "aws:///us-east-1a/i-0123456789",
+			wantProviderID: "aws:///us-east-1a/i-0123456789",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []client.Object{tt.machine}
+			if tt.nodeProviderID != "" {
+				objects = append(objects, &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+					Spec:       corev1.NodeSpec{ProviderID: tt.nodeProviderID},
+				})
+			}
+			scheme := runtime.NewScheme()
+			_ = machinev1beta1.AddToScheme(scheme)
+			_ = corev1.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+			recorder := record.NewFakeRecorder(10)
+
+			r := &ProviderIDMigrationReconciler{Client: fakeClient, EventRecorder: recorder}
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tt.machine)}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			got := &machinev1beta1.Machine{}
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, got); err != nil {
+				t.Fatalf("failed to get machine: %v", err)
+			}
+			if *got.Spec.ProviderID != tt.wantProviderID {
+				t.Errorf("providerID = %s, want %s", *got.Spec.ProviderID, tt.wantProviderID)
+			}
+			if len(recorder.Events) != tt.wantEvents {
+				t.Errorf("got %d events, want %d", len(recorder.Events), tt.wantEvents)
+			}
+
+			if tt.nodeProviderID == "" {
+				return
+			}
+			node := &corev1.Node{}
+			if err := fakeClient.Get(context.Background(), client.ObjectKey{Name: "worker-0"}, node); err != nil {
+				t.Fatalf("failed to get node: %v", err)
+			}
+			if got := node.Annotations[ProviderIDMismatchAnnotation]; got != tt.wantMismatch {
+				t.Errorf("mismatch annotation = %q, want %q", got, tt.wantMismatch)
+			}
+		})
+	}
+}
+
+func TestProviderIDMigrationReconciler_FlagNodeOnce(t *testing.T) {
+	machineObj := newMigrationMachine(legacyProviderID, "tenant-a")
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+		Spec:       corev1.NodeSpec{ProviderID: legacyProviderID},
+	}
+	scheme := runtime.NewScheme()
+	_ = machinev1beta1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machineObj, node).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &ProviderIDMigrationReconciler{Client: fakeClient, EventRecorder: recorder}
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(machineObj)}
+	for range 2 {
+		if _, err := r.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected a single ProviderIDMismatch event, got %d", len(recorder.Events))
+	}
+	if event := <-recorder.Events; !strings.Contains(event, ProviderIDMismatchReason) {
+		t.Errorf("expected a %s event, got %q", ProviderIDMismatchReason, event)
+	}
+
+	// The Node registers again with the new provider ID
+	if requests := r.nodeToLegacyMachines(context.Background(), &corev1.Node{
+		Spec: corev1.NodeSpec{ProviderID: migratedProviderID},
+	}); len(requests) != 1 || requests[0] != req {
+		t.Fatalf("expected the Node to map to its legacy Machine, got %v", requests)
+	}
+	if err := fakeClient.Delete(context.Background(), node); err != nil {
+		t.Fatal(err)
+	}
+	if err := fakeClient.Create(context.Background(), &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+		Spec:       corev1.NodeSpec{ProviderID: migratedProviderID},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	got := &machinev1beta1.Machine{}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, got); err != nil {
+		t.Fatal(err)
+	}
+	if *got.Spec.ProviderID != migratedProviderID {
+		t.Errorf("providerID = %s, want %s", *got.Spec.ProviderID, migratedProviderID)
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+	ncpv1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+)
+
+const (
+	// DefaultPowerCycleTimeout is how long a Machine may take to recover from a
+	// power cycle before it is re-imaged
+	DefaultPowerCycleTimeout = 10 * time.Minute
+
+	// DefaultReprovisionTimeout is how long a Machine may take to recover from
+	// a re-image before it is deleted
+	DefaultReprovisionTimeout = 60 * time.Minute
+)
+
+// RemediationReconciler implements the external remediation contract of the
+// MachineHealthCheck: it power cycles the instance of an unhealthy Machine,
+// then re-images it in place, then deletes the Machine, moving to the next
+// step when the Machine did not recover in time. The MachineHealthCheck
+// deletes the NvidiaCarbideRemediation once the Machine is healthy again.
+type RemediationReconciler struct {
+	client.Client
+	Actuator      *machine.Actuator
+	EventRecorder record.EventRecorder
+
+	// now returns the current time, for testing
+	now func() time.Time
+}
+
+// remediationStep is a remediation phase and how long it lasts
+type remediationStep struct {
+	phase   ncpv1.NvidiaCarbideRemediationPhase
+	timeout time.Duration
+}
+
+// remediationSteps returns the enabled steps of a remediation in order
+func remediationSteps(spec ncpv1.NvidiaCarbideRemediationSpec) []remediationStep {
+	timeout := func(d *metav1.Duration, defaultTimeout time.Duration) time.Duration {
+		if d == nil {
+			return defaultTimeout
+		}
+		return d.Duration
+	}
+
+	var steps []remediationStep
+	if t := timeout(spec.PowerCycleTimeout, DefaultPowerCycleTimeout); t > 0 {
+		steps = append(steps, remediationStep{ncpv1.RemediationPhasePowerCycling, t})
+	}
+	if t := timeout(spec.ReprovisionTimeout, DefaultReprovisionTimeout); t > 0 {
+		steps = append(steps, remediationStep{ncpv1.RemediationPhaseReprovisioning, t})
+	}
+	return append(steps, remediationStep{phase: ncpv1.RemediationPhaseDeleting})
+}
+
+// Reconcile runs the current remediation step and escalates to the next one
+// once it timed out or failed
+func (r *RemediationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	remediation := &ncpv1.NvidiaCarbideRemediation{}
+	if err := r.Get(ctx, req.NamespacedName, remediation); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !remediation.GetDeletionTimestamp().IsZero() ||
+		remediation.Status.Phase == ncpv1.RemediationPhaseDeleting {
+		return ctrl.Result{}, nil
+	}
+
+	machineObj := &machinev1beta1.Machine{}
+	if err := r.Get(ctx, remediatedMachineKey(remediation), machineObj); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("Machine to remediate not found")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !machineObj.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	steps := remediationSteps(remediation.Spec)
+	current := -1
+	for i, step := range steps {
+		if step.phase == remediation.Status.Phase {
+			current = i
+		}
+	}
+
+	// Start the first step, or the next one when the current step timed out
+	now := r.clock()
+	if current < 0 || remediation.Status.StepStartTime == nil {
+		return r.startStep(ctx, remediation, machineObj, steps[max(current, 0)], "Machine is unhealthy")
+	}
+	step := steps[current]
+	elapsed := now.Sub(remediation.Status.StepStartTime.Time)
+	if elapsed >= step.timeout {
+		return r.startStep(ctx, remediation, machineObj, steps[current+1],
+			fmt.Sprintf("Machine did not recover within %s of %s", step.timeout, step.phase))
+	}
+
+	// Run the current step, each step runs once per remediation
+	failed, err := r.runStep(ctx, remediation, machineObj, step)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if failed != "" {
+		return r.startStep(ctx, remediation, machineObj, steps[current+1],
+			fmt.Sprintf("%s failed: %s", step.phase, failed))
+	}
+	return ctrl.Result{RequeueAfter: step.timeout - elapsed}, nil
+}
+
+// startStep records a new step in the remediation status, then runs it
+func (r *RemediationReconciler) startStep(
+	ctx context.Context, remediation *ncpv1.NvidiaCarbideRemediation, machineObj *machinev1beta1.Machine,
+	step remediationStep, reason string,
+) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("Starting remediation step", "phase", step.phase, "reason", reason)
+
+	remediation.Status.Phase = step.phase
+	remediation.Status.StepStartTime = &metav1.Time{Time: r.clock()}
+	remediation.Status.Message = reason
+	if err := r.Status().Update(ctx, remediation); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update remediation status: %w", err)
+	}
+	if r.EventRecorder != nil {
+		r.EventRecorder.Eventf(machineObj, corev1.EventTypeWarning, "Remediation",
+			"%s, remediation phase is now %s", reason, step.phase)
+	}
+
+	// Run the step right away, the next reconcile checks its outcome
+	if _, err := r.runStep(ctx, remediation, machineObj, step); err != nil {
+		return ctrl.Result{}, err
+	}
+	if step.phase == ncpv1.RemediationPhaseDeleting {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: step.timeout}, nil
+}
+
+// runStep runs a remediation step. Reboot and reprovision requests are keyed on
+// the remediation and phase, so running a step again has no effect. The
+// message of a request rejected by Carbide is returned.
+func (r *RemediationReconciler) runStep(
+	ctx context.Context, remediation *ncpv1.NvidiaCarbideRemediation, machineObj *machinev1beta1.Machine,
+	step remediationStep,
+) (string, error) {
+	token := fmt.Sprintf("remediation-%s-%s", remediation.UID, step.phase)
+
+	var request *ncpv1.RequestStatus
+	var err error
+	switch step.phase {
+	case ncpv1.RemediationPhasePowerCycling:
+		request, err = r.Actuator.RequestReboot(ctx, machineObj, token)
+	case ncpv1.RemediationPhaseReprovisioning:
+		request, err = r.Actuator.RequestReprovision(ctx, machineObj, token)
+	case ncpv1.RemediationPhaseDeleting:
+		if err := r.Delete(ctx, machineObj); err != nil && !errors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to delete machine: %w", err)
+		}
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to run remediation step %s: %w", step.phase, err)
+	}
+	if request != nil && request.Result == ncpv1.RequestFailed {
+		return request.Message, nil
+	}
+	return "", nil
+}
+
+func (r *RemediationReconciler) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// remediatedMachineKey returns the Machine owning a remediation, which the
+// MachineHealthCheck names after the Machine
+func remediatedMachineKey(remediation *ncpv1.NvidiaCarbideRemediation) client.ObjectKey {
+	for _, ref := range remediation.GetOwnerReferences() {
+		if ref.Kind == "Machine" && ref.APIVersion == machinev1beta1.SchemeGroupVersion.String() {
+			return client.ObjectKey{Namespace: remediation.Namespace, Name: ref.Name}
+		}
+	}
+	return client.ObjectKeyFromObject(remediation)
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *RemediationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ncpv1.NvidiaCarbideRemediation{}).
+		Complete(r)
+}
+
+// SetupRemediationController creates and registers the remediation controller
+// with the manager
+func SetupRemediationController(mgr ctrl.Manager, actuator *machine.Actuator) error {
+	reconciler := &RemediationReconciler{
+		Client:        mgr.GetClient(),
+		Actuator:      actuator,
+		EventRecorder: mgr.GetEventRecorderFor("nvidia-carbide-remediation-controller"),
+	}
+
+	return reconciler.SetupWithManager(mgr)
+}
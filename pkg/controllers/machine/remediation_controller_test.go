@@ -0,0 +1,174 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+	ncpv1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	bmm "github.com/nvidia/bare-metal-manager-rest/sdk/standard"
+)
+
+// fakeRemediationClient counts reboots and re-images
+type fakeRemediationClient struct {
+	machine.NvidiaCarbideClientInterface
+	reboots    int
+	reimages   int
+	rebootErr  error
+	reimageErr error
+}
+
+func (f *fakeRemediationClient) RebootInstance(_ context.Context, _, _ string) (*http.Response, error) {
+	f.reboots++
+	return &http.Response{StatusCode: http.StatusOK}, f.rebootErr
+}
+
+func (f *fakeRemediationClient) UpdateInstance(
+	_ context.Context, _, instanceID string, _ bmm.InstanceUpdateRequest,
+) (*bmm.Instance, *http.Response, error) {
+	f.reimages++
+	return &bmm.Instance{Id: &instanceID}, &http.Response{StatusCode: http.StatusOK}, f.reimageErr
+}
+
+func TestRemediationReconciler(t *testing.T) {
+	type check struct {
+		after        time.Duration
+		wantPhase    ncpv1.NvidiaCarbideRemediationPhase
+		wantReboots  int
+		wantReimages int
+	}
+	zero := &metav1.Duration{}
+
+	tests := []struct {
+		name      string
+		spec      ncpv1.NvidiaCarbideRemediationSpec
+		rebootErr error
+		checks    []check
+	}{
+		{
+			name: "escalates on timeouts",
+			checks: []check{
+				{0, ncpv1.RemediationPhasePowerCycling, 1, 0},
+				{5 * time.Minute, ncpv1.RemediationPhasePowerCycling, 1, 0},
+				{11 * time.Minute, ncpv1.RemediationPhaseReprovisioning, 1, 1},
+				{30 * time.Minute, ncpv1.RemediationPhaseReprovisioning, 1, 1},
+				{72 * time.Minute, ncpv1.RemediationPhaseDeleting, 1, 1},
+			},
+		},
+		{
+			name:      "escalates when a step fails",
+			rebootErr: errors.New("409 Conflict"),
+			checks: []check{
+				{0, ncpv1.RemediationPhasePowerCycling, 1, 0},
+				{time.Second, ncpv1.RemediationPhaseReprovisioning, 1, 1},
+			},
+		},
+		{
+			name: "zero timeouts skip steps",
+			spec: ncpv1.NvidiaCarbideRemediationSpec{PowerCycleTimeout: zero, ReprovisionTimeout: zero},
+			checks: []check{
+				{0, ncpv1.RemediationPhaseDeleting, 0, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machineObj := &machinev1beta1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: "openshift-machine-api"},
+				Spec: machinev1beta1.MachineSpec{
+					ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{
+						Raw: []byte(`{"siteId":"site","credentialsSecret":{"name":"creds"}}`),
+					}},
+				},
+				Status: machinev1beta1.MachineStatus{
+					ProviderStatus: &runtime.RawExtension{Raw: []byte(`{"instanceId":"instance-1"}`)},
+				},
+			}
+			remediation := &ncpv1.NvidiaCarbideRemediation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "worker-0",
+					Namespace: "openshift-machine-api",
+					UID:       "uid-1",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: machinev1beta1.SchemeGroupVersion.String(),
+						Kind:       "Machine",
+						Name:       "worker-0",
+					}},
+				},
+				Spec: tt.spec,
+			}
+
+			scheme := runtime.NewScheme()
+			_ = machinev1beta1.AddToScheme(scheme)
+			_ = ncpv1.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(machineObj, remediation).
+				WithStatusSubresource(machineObj, remediation).Build()
+			carbideClient := &fakeRemediationClient{rebootErr: tt.rebootErr}
+
+			start := time.Now()
+			now := start
+			r := &RemediationReconciler{
+				Client:        fakeClient,
+				Actuator:      machine.NewActuatorWithClient(fakeClient, nil, carbideClient, "test-org"),
+				EventRecorder: record.NewFakeRecorder(10),
+				now:           func() time.Time { return now },
+			}
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(remediation)}
+
+			for _, c := range tt.checks {
+				now = start.Add(c.after)
+				if _, err := r.Reconcile(context.Background(), req); err != nil {
+					t.Fatalf("after %s: Reconcile() error = %v", c.after, err)
+				}
+
+				got := &ncpv1.NvidiaCarbideRemediation{}
+				if err := fakeClient.Get(context.Background(), req.NamespacedName, got); err != nil {
+					t.Fatal(err)
+				}
+				if got.Status.Phase != c.wantPhase {
+					t.Errorf("after %s: phase = %s, want %s", c.after, got.Status.Phase, c.wantPhase)
+				}
+				if carbideClient.reboots != c.wantReboots || carbideClient.reimages != c.wantReimages {
+					t.Errorf("after %s: got %d reboots and %d re-images, want %d and %d", c.after,
+						carbideClient.reboots, carbideClient.reimages, c.wantReboots, c.wantReimages)
+				}
+
+				err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(machineObj),
+					&machinev1beta1.Machine{})
+				if deleted := apierrors.IsNotFound(err); deleted != (c.wantPhase == ncpv1.RemediationPhaseDeleting) {
+					t.Errorf("after %s: machine deleted = %t, error %v", c.after, deleted, err)
+				}
+			}
+		})
+	}
+}
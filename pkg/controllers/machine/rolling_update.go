@@ -0,0 +1,372 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// RollingUpdateAnnotation opts a MachineSet into replacing Machines whose
+	// provider spec differs from the template when set to "true"
+	RollingUpdateAnnotation = "nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/rolling-update"
+
+	// MaxSurgeAnnotation is the number or percentage of Machines created above
+	// the desired replicas during a rolling update, 1 by default
+	MaxSurgeAnnotation = "nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/max-surge"
+
+	// MaxUnavailableAnnotation is the number or percentage of desired replicas
+	// that may be without a Ready Node during a rolling update, 0 by default
+	MaxUnavailableAnnotation = "nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/max-unavailable"
+
+	// UpdatedReplicasAnnotation reports the number of Machines matching the
+	// MachineSet template, as MachineSet status has no field for it
+	UpdatedReplicasAnnotation = "nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/updated-replicas"
+
+	// ProviderSpecHashAnnotation records on a Machine the hash of the template
+	// provider spec it was created from
+	ProviderSpecHashAnnotation = "nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/provider-spec-hash"
+
+	// MachinesUpdatedCondition is the MachineSet status condition reporting
+	// the progress of a rolling update. It is False while Machines created
+	// from an older template remain.
+	MachinesUpdatedCondition machinev1beta1.ConditionType = "MachinesUpdated"
+
+	// RollingUpdateInProgressReason is the MachinesUpdated reason while
+	// outdated Machines are being replaced
+	RollingUpdateInProgressReason = "RollingUpdateInProgress"
+
+	// MachinesUpToDateReason is the MachinesUpdated reason once every Machine
+	// matches the template
+	MachinesUpToDateReason = "MachinesUpToDate"
+
+	// rollingUpdateRequeueAfter bounds how long a rolling update waits for Node
+	// readiness when no watch event arrives
+	rollingUpdateRequeueAfter = 30 * time.Second
+)
+
+// rollingUpdateEnabled reports whether the MachineSet opted into rolling updates
+func rollingUpdateEnabled(machineSet *machinev1beta1.MachineSet) bool {
+	enabled, _ := strconv.ParseBool(machineSet.Annotations[RollingUpdateAnnotation])
+	return enabled
+}
+
+// providerSpecHash returns a hash of a provider spec that does not depend on
+// the field order of its serialization
+func providerSpecHash(value *runtime.RawExtension) (string, error) {
+	var raw []byte
+	if value != nil {
+		raw = value.Raw
+	}
+
+	var normalized interface{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &normalized); err != nil {
+			return "", fmt.Errorf("failed to decode provider spec: %w", err)
+		}
+	}
+	canonical, err := json.Marshal(normalized)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode provider spec: %w", err)
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// rollingUpdateLimits returns the maxSurge and maxUnavailable Machine counts
+// for the desired replicas. At least one of them is positive so that the
+// update can progress.
+func rollingUpdateLimits(machineSet *machinev1beta1.MachineSet, desired int) (int, int, error) {
+	parse := func(key, defaultValue string, roundUp bool) (int, error) {
+		value, ok := machineSet.Annotations[key]
+		if !ok {
+			value = defaultValue
+		}
+		intOrPercent := intstr.Parse(value)
+		scaled, err := intstr.GetScaledValueFromIntOrPercent(&intOrPercent, desired, roundUp)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s annotation: %w", key, err)
+		}
+		if scaled < 0 {
+			return 0, fmt.Errorf("invalid %s annotation: %q is negative", key, value)
+		}
+		return scaled, nil
+	}
+
+	maxSurge, err := parse(MaxSurgeAnnotation, "1", true)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxUnavailable, err := parse(MaxUnavailableAnnotation, "0", false)
+	if err != nil {
+		return 0, 0, err
+	}
+	if maxSurge == 0 && maxUnavailable == 0 {
+		maxSurge = 1
+	}
+	return maxSurge, maxUnavailable, nil
+}
+
+// rollingUpdate replaces Machines whose provider spec differs from the
+// template. Replacements are created up to maxSurge Machines above the desired
+// replicas, and outdated Machines are deleted, with or without a Ready Node,
+// as long as no more than maxUnavailable desired replicas are unavailable.
+func (r *MachineSetReconciler) rollingUpdate(
+	ctx context.Context,
+	machineSet *machinev1beta1.MachineSet,
+	updated, outdated []*machinev1beta1.Machine,
+	desiredReplicas int,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	maxSurge, maxUnavailable, err := rollingUpdateLimits(machineSet, desiredReplicas)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Create replacements without exceeding the surge
+	total := len(updated) + len(outdated)
+	toCreate := min(desiredReplicas+maxSurge-total, desiredReplicas-len(updated))
+	if toCreate > 0 {
+		logger.Info("Creating replacement machines", "count", toCreate)
+		created := make([]*machinev1beta1.Machine, 0, toCreate)
+		for i := 0; i < toCreate; i++ {
+			machineObj, err := r.createMachine(ctx, machineSet)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			created = append(created, machineObj)
+		}
+		if err := r.waitForCache(ctx, created, true); err != nil {
+			return ctrl.Result{}, err
+		}
+		updated = append(updated, created...)
+		total += toCreate
+	}
+
+	ready := 0
+	for _, machineObj := range updated {
+		readySince, err := r.nodeReadySince(ctx, machineObj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if readySince != nil {
+			ready++
+		}
+	}
+	updatedUnavailable := len(updated) - ready
+	var readyOutdated, notReadyOutdated []*machinev1beta1.Machine
+	for _, machineObj := range outdated {
+		readySince, err := r.nodeReadySince(ctx, machineObj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if readySince == nil {
+			notReadyOutdated = append(notReadyOutdated, machineObj)
+			continue
+		}
+		ready++
+		readyOutdated = append(readyOutdated, machineObj)
+	}
+
+	// As for Deployments, no more Machines are deleted than would leave fewer
+	// than desiredReplicas-maxUnavailable Machines once the updated ones that
+	// are not Ready yet are left aside. Outdated Machines without a Ready Node
+	// are deleted first, then Ready ones as long as enough Nodes stay Ready.
+	minAvailable := desiredReplicas - maxUnavailable
+	budget := total - minAvailable - updatedUnavailable
+	var toDelete []*machinev1beta1.Machine
+	if count := min(budget, len(notReadyOutdated)); count > 0 {
+		notReadyToDelete, err := getMachinesToDelete(notReadyOutdated, count, machineSet.Spec.DeletePolicy)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		toDelete = append(toDelete, notReadyToDelete...)
+		budget -= count
+	}
+	if count := min(budget, ready-minAvailable); count > 0 {
+		readyToDelete, err := getMachinesToDelete(readyOutdated, count, machineSet.Spec.DeletePolicy)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		toDelete = append(toDelete, readyToDelete...)
+	}
+
+	if len(toDelete) > 0 {
+		logger.Info("Deleting outdated machines", "count", len(toDelete))
+		for _, machineObj := range toDelete {
+			if err := r.deleteMachine(ctx, machineObj); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if err := r.waitForCache(ctx, toDelete, false); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: rollingUpdateRequeueAfter}, nil
+}
+
+// stampProviderSpecHash records the hash of their own provider spec on Machines
+// created before rolling updates were enabled, so that only those differing
+// from the template are replaced
+func (r *MachineSetReconciler) stampProviderSpecHash(ctx context.Context, machines []*machinev1beta1.Machine) error {
+	for _, machineObj := range machines {
+		if _, ok := machineObj.Annotations[ProviderSpecHashAnnotation]; ok {
+			continue
+		}
+		hash, err := providerSpecHash(machineObj.Spec.ProviderSpec.Value)
+		if err != nil {
+			return fmt.Errorf("failed to hash provider spec of machine %s: %w", machineObj.Name, err)
+		}
+		patchBase := client.MergeFrom(machineObj.DeepCopy())
+		if machineObj.Annotations == nil {
+			machineObj.Annotations = map[string]string{}
+		}
+		machineObj.Annotations[ProviderSpecHashAnnotation] = hash
+		if err := r.Patch(ctx, machineObj, patchBase); err != nil {
+			return fmt.Errorf("failed to patch provider spec hash of machine %s: %w", machineObj.Name, err)
+		}
+	}
+	return nil
+}
+
+// partitionMachines splits Machines into those created from the current
+// template and those created from an older one
+func partitionMachines(
+	machineSet *machinev1beta1.MachineSet, machines []*machinev1beta1.Machine,
+) (updated, outdated []*machinev1beta1.Machine, err error) {
+	templateHash, err := providerSpecHash(machineSet.Spec.Template.Spec.ProviderSpec.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, machineObj := range machines {
+		if machineObj.Annotations[ProviderSpecHashAnnotation] == templateHash {
+			updated = append(updated, machineObj)
+		} else {
+			outdated = append(outdated, machineObj)
+		}
+	}
+	return updated, outdated, nil
+}
+
+// updateUpdatedReplicas reports the number of up-to-date Machines in an annotation
+func (r *MachineSetReconciler) updateUpdatedReplicas(
+	ctx context.Context, machineSet *machinev1beta1.MachineSet, updatedReplicas int,
+) error {
+	value := strconv.Itoa(updatedReplicas)
+	if machineSet.Annotations[UpdatedReplicasAnnotation] == value {
+		return nil
+	}
+
+	patchBase := client.MergeFrom(machineSet.DeepCopy())
+	if machineSet.Annotations == nil {
+		machineSet.Annotations = map[string]string{}
+	}
+	machineSet.Annotations[UpdatedReplicasAnnotation] = value
+	if err := r.Patch(ctx, machineSet, patchBase); err != nil {
+		return fmt.Errorf("failed to patch MachineSet updated replicas: %w", err)
+	}
+	return nil
+}
+
+// updateRollingUpdateCondition reports the updated and outdated Machine counts
+// in the MachinesUpdated status condition. The MachineSet type of the Machine
+// API has no conditions although its CRD has, so the status is read and
+// patched unstructured.
+func (r *MachineSetReconciler) updateRollingUpdateCondition(
+	ctx context.Context, machineSet *machinev1beta1.MachineSet, updated, outdated int,
+) error {
+	condition := machinev1beta1.Condition{
+		Type:    MachinesUpdatedCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  MachinesUpToDateReason,
+		Message: fmt.Sprintf("%d machines updated, %d outdated", updated, outdated),
+	}
+	if outdated > 0 {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = RollingUpdateInProgressReason
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(machineSetKind)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(machineSet), current); err != nil {
+		return fmt.Errorf("failed to get MachineSet conditions: %w", err)
+	}
+	rawConditions, _, err := unstructured.NestedSlice(current.Object, "status", "conditions")
+	if err != nil {
+		return fmt.Errorf("failed to read MachineSet conditions: %w", err)
+	}
+
+	index := -1
+	for i, rawCondition := range rawConditions {
+		if existing, ok := rawCondition.(map[string]interface{}); ok && existing["type"] == string(condition.Type) {
+			index = i
+			break
+		}
+	}
+
+	condition.LastTransitionTime = metav1.Now()
+	if index >= 0 {
+		existing := &machinev1beta1.Condition{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(
+			rawConditions[index].(map[string]interface{}), existing)
+		if err == nil && existing.Status == condition.Status {
+			if existing.Reason == condition.Reason && existing.Message == condition.Message {
+				return nil
+			}
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+
+	value, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&condition)
+	if err != nil {
+		return fmt.Errorf("failed to encode MachineSet condition: %w", err)
+	}
+	if index >= 0 {
+		rawConditions[index] = value
+	} else {
+		rawConditions = append(rawConditions, value)
+	}
+
+	patchBase := client.MergeFrom(current.DeepCopy())
+	if err := unstructured.SetNestedSlice(current.Object, rawConditions, "status", "conditions"); err != nil {
+		return fmt.Errorf("failed to set MachineSet conditions: %w", err)
+	}
+	if err := r.Status().Patch(ctx, current, patchBase); err != nil {
+		return fmt.Errorf("failed to patch MachineSet %s condition: %w", condition.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/providerid"
+)
+
+const (
+	// collectTimeout bounds the Machine list performed on each scrape
+	collectTimeout = 10 * time.Second
+
+	// unknownLabel is used when a Machine has no phase or instance state yet
+	unknownLabel = "Unknown"
+)
+
+var (
+	machinesByPhaseDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "machines_by_phase"),
+		"Number of Machines by Machine API phase.",
+		[]string{"phase"}, nil,
+	)
+
+	machinesByInstanceStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "machines_by_instance_state"),
+		"Number of Machines by NVIDIA Carbide instance state.",
+		[]string{"state"}, nil,
+	)
+
+	legacyProviderIDsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "machines_with_legacy_provider_id"),
+		"Number of Machines with a legacy provider ID without tenant.",
+		nil, nil,
+	)
+)
+
+// MachineCollector reports gauges computed from the current set of Machines
+// of this provider
+type MachineCollector struct {
+	reader            client.Reader
+	isOwnProviderSpec func(*runtime.RawExtension) (bool, error)
+}
+
+// NewMachineCollector creates a collector that lists Machines through reader on
+// each scrape. Only Machines whose provider spec isOwnProviderSpec accepts are
+// counted; it is the actuator check, which this package cannot import.
+func NewMachineCollector(
+	reader client.Reader, isOwnProviderSpec func(*runtime.RawExtension) (bool, error),
+) *MachineCollector {
+	return &MachineCollector{reader: reader, isOwnProviderSpec: isOwnProviderSpec}
+}
+
+// Describe implements prometheus.Collector
+func (c *MachineCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- machinesByPhaseDesc
+	ch <- machinesByInstanceStateDesc
+	ch <- legacyProviderIDsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *MachineCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	machineList := &machinev1beta1.MachineList{}
+	if err := c.reader.List(ctx, machineList); err != nil {
+		log.Log.WithName("metrics").Error(err, "failed to list machines for metrics collection")
+		return
+	}
+
+	byPhase := map[string]int{}
+	byState := map[string]int{}
+	legacyProviderIDs := 0
+	for i := range machineList.Items {
+		m := &machineList.Items[i]
+		// Machines of other providers are not counted
+		if own, err := c.isOwnProviderSpec(m.Spec.ProviderSpec.Value); err != nil || !own {
+			continue
+		}
+
+		phase := unknownLabel
+		if m.Status.Phase != nil && *m.Status.Phase != "" {
+			phase = *m.Status.Phase
+		}
+		byPhase[phase]++
+
+		byState[instanceState(m)]++
+
+		if hasLegacyProviderID(m) {
+			legacyProviderIDs++
+		}
+	}
+
+	for phase, count := range byPhase {
+		ch <- prometheus.MustNewConstMetric(machinesByPhaseDesc, prometheus.GaugeValue, float64(count), phase)
+	}
+	for state, count := range byState {
+		ch <- prometheus.MustNewConstMetric(machinesByInstanceStateDesc, prometheus.GaugeValue, float64(count), state)
+	}
+	ch <- prometheus.MustNewConstMetric(legacyProviderIDsDesc, prometheus.GaugeValue, float64(legacyProviderIDs))
+}
+
+// hasLegacyProviderID reports whether the Machine has a provider ID of this
+// provider in the legacy format
+func hasLegacyProviderID(m *machinev1beta1.Machine) bool {
+	if m.Spec.ProviderID == nil {
+		return false
+	}
+	pid, err := providerid.ParseProviderID(*m.Spec.ProviderID)
+	return err == nil && pid.IsLegacy()
+}
+
+// instanceState extracts the Carbide instance state from the Machine provider status
+func instanceState(m *machinev1beta1.Machine) string {
+	if m.Status.ProviderStatus == nil {
+		return unknownLabel
+	}
+	providerStatus := &v1.NvidiaCarbideMachineProviderStatus{}
+	if err := json.Unmarshal(m.Status.ProviderStatus.Raw, providerStatus); err != nil {
+		return unknownLabel
+	}
+	if providerStatus.InstanceState == nil || *providerStatus.InstanceState == "" {
+		return unknownLabel
+	}
+	return *providerStatus.InstanceState
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/metrics"
+)
+
+func TestMachineCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = machinev1beta1.AddToScheme(scheme)
+
+	running := "Running"
+	legacyProviderID := "nvidia-carbide://org/site/550e8400-e29b-41d4-a716-446655440000"
+	ownProviderSpec := machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{
+		Raw: []byte(`{"apiVersion":"nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/v1","kind":"NvidiaCarbideMachineProviderSpec"}`),
+	}}
+	machines := []*machinev1beta1.Machine{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "default"},
+			Spec:       machinev1beta1.MachineSpec{ProviderSpec: ownProviderSpec, ProviderID: &legacyProviderID},
+			Status: machinev1beta1.MachineStatus{
+				Phase:          &running,
+				ProviderStatus: &runtime.RawExtension{Raw: []byte(`{"instanceState":"Ready"}`)},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default"},
+			Spec:       machinev1beta1.MachineSpec{ProviderSpec: ownProviderSpec},
+		},
+		{
+			// Provider specs without kind are handled by this provider too
+			ObjectMeta: metav1.ObjectMeta{Name: "kind-less", Namespace: "default"},
+			Spec: machinev1beta1.MachineSpec{ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{
+				Raw: []byte(`{"siteId":"site"}`),
+			}}},
+			Status: machinev1beta1.MachineStatus{Phase: &running},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-provider", Namespace: "default"},
+			Spec: machinev1beta1.MachineSpec{ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{
+				Raw: []byte(`{"apiVersion":"machine.openshift.io/v1beta1","kind":"AWSMachineProviderConfig"}`),
+			}}},
+			Status: machinev1beta1.MachineStatus{Phase: &running},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-provider-spec", Namespace: "default"},
+			Status:     machinev1beta1.MachineStatus{Phase: &running},
+		},
+	}
+
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, m := range machines {
+		builder = builder.WithObjects(m)
+	}
+
+	expected := `
+# HELP nvidia_carbide_machines_by_instance_state Number of Machines by NVIDIA Carbide instance state.
+# TYPE nvidia_carbide_machines_by_instance_state gauge
+nvidia_carbide_machines_by_instance_state{state="Ready"} 1
+nvidia_carbide_machines_by_instance_state{state="Unknown"} 2
+# HELP nvidia_carbide_machines_by_phase Number of Machines by Machine API phase.
+# TYPE nvidia_carbide_machines_by_phase gauge
+nvidia_carbide_machines_by_phase{phase="Running"} 2
+nvidia_carbide_machines_by_phase{phase="Unknown"} 1
+# HELP nvidia_carbide_machines_with_legacy_provider_id Number of Machines with a legacy provider ID without tenant.
+# TYPE nvidia_carbide_machines_with_legacy_provider_id gauge
+nvidia_carbide_machines_with_legacy_provider_id 1
+`
+	collector := metrics.NewMachineCollector(builder.Build(), machine.IsOwnProviderSpec)
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "nvidia_carbide"
+
+	// ResultSuccess labels a successful operation
+	ResultSuccess = "success"
+
+	// ResultFailure labels a failed operation
+	ResultFailure = "failure"
+
+	// codeError is the status code label used when no HTTP response was received
+	codeError = "error"
+)
+
+var (
+	// APIRequestDuration tracks Carbide REST API latency by operation and status code
+	APIRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "api_request_duration_seconds",
+			Help:      "Latency of NVIDIA Carbide REST API requests by operation and HTTP status code.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"operation", "code"},
+	)
+
+	// MachineCreateTotal counts instance creations by result
+	MachineCreateTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "machine_create_total",
+			Help:      "Number of instance create attempts by result.",
+		},
+		[]string{"result"},
+	)
+
+	// MachineDeleteTotal counts instance deletions by result
+	MachineDeleteTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "machine_delete_total",
+			Help:      "Number of instance delete attempts by result.",
+		},
+		[]string{"result"},
+	)
+
+	// ProviderIDMigrationTotal counts legacy provider ID rewrites by result
+	ProviderIDMigrationTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "provider_id_migration_total",
+			Help:      "Number of legacy Machine provider IDs rewritten to the tenant-aware format by result.",
+		},
+		[]string{"result"},
+	)
+
+	// MachineProvisioningDuration tracks the time from Machine creation to instance Ready
+	MachineProvisioningDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "machine_provisioning_duration_seconds",
+			Help:      "Time from Machine creation until the NVIDIA Carbide instance reports Ready.",
+			// Bare-metal provisioning takes minutes to hours
+			Buckets: []float64{60, 120, 300, 600, 900, 1200, 1800, 2700, 3600, 5400, 7200, 10800},
+		},
+	)
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		APIRequestDuration,
+		MachineCreateTotal,
+		MachineDeleteTotal,
+		ProviderIDMigrationTotal,
+		MachineProvisioningDuration,
+	)
+}
+
+// ObserveAPIRequest records the latency of a Carbide API call started at start.
+// A nil response is recorded with the "error" status code label.
+func ObserveAPIRequest(operation string, start time.Time, resp *http.Response) {
+	code := codeError
+	if resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	APIRequestDuration.WithLabelValues(operation, code).Observe(time.Since(start).Seconds())
+}
+
+// RecordCreate increments the create counter for the given error outcome
+func RecordCreate(err error) {
+	MachineCreateTotal.WithLabelValues(result(err)).Inc()
+}
+
+// RecordDelete increments the delete counter for the given error outcome
+func RecordDelete(err error) {
+	MachineDeleteTotal.WithLabelValues(result(err)).Inc()
+}
+
+// RecordProviderIDMigration increments the provider ID migration counter for
+// the given error outcome
+func RecordProviderIDMigration(err error) {
+	ProviderIDMigrationTotal.WithLabelValues(result(err)).Inc()
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveAPIRequest(t *testing.T) {
+	APIRequestDuration.Reset()
+
+	ObserveAPIRequest("GetInstance", time.Now(), &http.Response{StatusCode: http.StatusOK})
+	ObserveAPIRequest("GetInstance", time.Now(), nil)
+
+	if got := testutil.CollectAndCount(APIRequestDuration); got != 2 {
+		t.Errorf("expected 2 label combinations, got %d", got)
+	}
+}
+
+func TestRecordCreate(t *testing.T) {
+	MachineCreateTotal.Reset()
+
+	RecordCreate(nil)
+	RecordCreate(nil)
+	RecordCreate(errors.New("boom"))
+
+	if got := testutil.ToFloat64(MachineCreateTotal.WithLabelValues(ResultSuccess)); got != 2 {
+		t.Errorf("expected 2 successes, got %v", got)
+	}
+	if got := testutil.ToFloat64(MachineCreateTotal.WithLabelValues(ResultFailure)); got != 1 {
+		t.Errorf("expected 1 failure, got %v", got)
+	}
+}
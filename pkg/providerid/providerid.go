@@ -17,7 +17,9 @@ limitations under the License.
 package providerid
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/google/uuid"
@@ -27,6 +29,9 @@ const ProviderPrefix = "nvidia-carbide://"
 
 // ProviderID represents a parsed NVIDIA Carbide provider ID.
 // Format: nvidia-carbide://org/tenant/site/instance-id
+//
+// Segments are percent-escaped in the string form, so names may contain
+// slashes. Segments holding a UUID are kept in their canonical lowercase form.
 type ProviderID struct {
 	OrgName    string
 	TenantName string
@@ -34,19 +39,60 @@ type ProviderID struct {
 	InstanceID uuid.UUID
 }
 
-// NewProviderID creates a new ProviderID.
-func NewProviderID(orgName, tenantName, siteName string, instanceID uuid.UUID) *ProviderID {
+// NewProviderID creates a new ProviderID in the tenant-aware format.
+func NewProviderID(orgName, tenantName, siteName string, instanceID uuid.UUID) (*ProviderID, error) {
+	var errs []error
+	if orgName == "" {
+		errs = append(errs, errors.New("org name must not be empty"))
+	}
+	if tenantName == "" {
+		errs = append(errs, errors.New("tenant name must not be empty"))
+	}
+	if siteName == "" {
+		errs = append(errs, errors.New("site name must not be empty"))
+	}
+	if instanceID == uuid.Nil {
+		errs = append(errs, errors.New("instance ID must not be the nil UUID"))
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid provider ID: %w", errors.Join(errs...))
+	}
+
 	return &ProviderID{
-		OrgName:    orgName,
-		TenantName: tenantName,
-		SiteName:   siteName,
+		OrgName:    normalizeSegment(orgName),
+		TenantName: normalizeSegment(tenantName),
+		SiteName:   normalizeSegment(siteName),
 		InstanceID: instanceID,
-	}
+	}, nil
 }
 
-// String returns the provider ID string representation.
+// String returns the provider ID string representation. Legacy provider IDs
+// keep the 3-segment format.
 func (p *ProviderID) String() string {
-	return fmt.Sprintf("%s%s/%s/%s/%s", ProviderPrefix, p.OrgName, p.TenantName, p.SiteName, p.InstanceID.String())
+	if p.IsLegacy() {
+		return fmt.Sprintf("%s%s/%s/%s", ProviderPrefix,
+			url.PathEscape(p.OrgName), url.PathEscape(p.SiteName), p.InstanceID.String())
+	}
+	return fmt.Sprintf("%s%s/%s/%s/%s", ProviderPrefix, url.PathEscape(p.OrgName),
+		url.PathEscape(p.TenantName), url.PathEscape(p.SiteName), p.InstanceID.String())
+}
+
+// IsLegacy reports whether the provider ID was parsed from the legacy
+// 3-segment format, which has no tenant.
+func (p *ProviderID) IsLegacy() bool {
+	return p.TenantName == ""
+}
+
+// Equal reports whether two provider IDs identify the same instance. Names are
+// compared case-insensitively.
+func (p *ProviderID) Equal(other *ProviderID) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+	return strings.EqualFold(p.OrgName, other.OrgName) &&
+		strings.EqualFold(p.TenantName, other.TenantName) &&
+		strings.EqualFold(p.SiteName, other.SiteName) &&
+		p.InstanceID == other.InstanceID
 }
 
 // ParseProviderID parses a provider ID string.
@@ -59,33 +105,49 @@ func ParseProviderID(providerIDStr string) (*ProviderID, error) {
 
 	trimmed := strings.TrimPrefix(providerIDStr, ProviderPrefix)
 	parts := strings.Split(trimmed, "/")
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, fmt.Errorf("invalid provider ID format, expected 3 or 4 segments: %s", providerIDStr)
+	}
 
-	switch len(parts) {
-	case 3:
-		// Legacy format: nvidia-carbide://org/site/instance-id
-		instanceID, err := uuid.Parse(parts[2])
+	segments := make([]string, len(parts)-1)
+	for i, part := range parts[:len(parts)-1] {
+		segment, err := url.PathUnescape(part)
 		if err != nil {
-			return nil, fmt.Errorf("invalid instance ID %q: %w", parts[2], err)
+			return nil, fmt.Errorf("invalid provider ID segment %q: %w", part, err)
 		}
-		return &ProviderID{
-			OrgName:    parts[0],
-			TenantName: "",
-			SiteName:   parts[1],
-			InstanceID: instanceID,
-		}, nil
-	case 4:
-		// New format: nvidia-carbide://org/tenant/site/instance-id
-		instanceID, err := uuid.Parse(parts[3])
-		if err != nil {
-			return nil, fmt.Errorf("invalid instance ID %q: %w", parts[3], err)
+		segments[i] = normalizeSegment(segment)
+	}
+	instanceID, err := uuid.Parse(parts[len(parts)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid instance ID %q: %w", parts[len(parts)-1], err)
+	}
+
+	pid := &ProviderID{InstanceID: instanceID}
+	if len(segments) == 2 {
+		// Legacy format: nvidia-carbide://org/site/instance-id
+		pid.OrgName, pid.SiteName = segments[0], segments[1]
+	} else {
+		// New format: nvidia-carbide://org/tenant/site/instance-id. An empty
+		// tenant would be taken for the legacy format and printed without it.
+		pid.OrgName, pid.TenantName, pid.SiteName = segments[0], segments[1], segments[2]
+		if pid.TenantName == "" {
+			return nil, fmt.Errorf("invalid provider ID format, tenant must not be empty: %s", providerIDStr)
 		}
-		return &ProviderID{
-			OrgName:    parts[0],
-			TenantName: parts[1],
-			SiteName:   parts[2],
-			InstanceID: instanceID,
-		}, nil
-	default:
-		return nil, fmt.Errorf("invalid provider ID format, expected 3 or 4 segments: %s", providerIDStr)
 	}
+	if pid.OrgName == "" || pid.SiteName == "" {
+		return nil, fmt.Errorf("invalid provider ID format, org and site must not be empty: %s", providerIDStr)
+	}
+	return pid, nil
+}
+
+// normalizeSegment returns the canonical lowercase form of a segment holding
+// a UUID, and any other segment unchanged.
+func normalizeSegment(segment string) string {
+	if len(segment) != 36 {
+		return segment
+	}
+	if id, err := uuid.Parse(segment); err == nil {
+		return id.String()
+	}
+	return segment
 }
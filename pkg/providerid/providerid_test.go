@@ -0,0 +1,198 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providerid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+const testInstanceID = "6f1c6f3e-8c7a-4d8e-9a2b-0c4f2d6e8a10"
+
+func TestNewProviderID(t *testing.T) {
+	instanceID := uuid.MustParse(testInstanceID)
+
+	pid, err := NewProviderID("my/org", "A1B2C3D4-0000-4000-8000-00000000000F", "site a", instanceID)
+	if err != nil {
+		t.Fatalf("NewProviderID() error = %v", err)
+	}
+	want := "nvidia-carbide://my%2Forg/a1b2c3d4-0000-4000-8000-00000000000f/site%20a/" + testInstanceID
+	if pid.String() != want {
+		t.Errorf("String() = %s, want %s", pid.String(), want)
+	}
+
+	for _, tt := range []struct {
+		name              string
+		org, tenant, site string
+		instanceID        uuid.UUID
+		wantErr           string
+	}{
+		{"empty org", "", "tenant", "site", instanceID, "org name"},
+		{"empty tenant", "org", "", "site", instanceID, "tenant name"},
+		{"empty site", "org", "tenant", "", instanceID, "site name"},
+		{"nil instance ID", "org", "tenant", "site", uuid.Nil, "nil UUID"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProviderID(tt.org, tt.tenant, tt.site, tt.instanceID)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewProviderID() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseProviderID(t *testing.T) {
+	tests := []struct {
+		name       string
+		providerID string
+		want       *ProviderID
+		wantErr    bool
+	}{
+		{
+			name:       "tenant-aware format",
+			providerID: "nvidia-carbide://org/tenant/site/" + testInstanceID,
+			want:       &ProviderID{"org", "tenant", "site", uuid.MustParse(testInstanceID)},
+		},
+		{
+			name:       "legacy format",
+			providerID: "nvidia-carbide://org/site/" + testInstanceID,
+			want:       &ProviderID{"org", "", "site", uuid.MustParse(testInstanceID)},
+		},
+		{
+			name:       "escaped segments",
+			providerID: "nvidia-carbide://my%2Forg/ten%25ant/site%20a/" + testInstanceID,
+			want:       &ProviderID{"my/org", "ten%ant", "site a", uuid.MustParse(testInstanceID)},
+		},
+		{
+			name:       "uppercase UUIDs are canonicalized",
+			providerID: "nvidia-carbide://org/A1B2C3D4-0000-4000-8000-00000000000F/site/" + strings.ToUpper(testInstanceID),
+			want: &ProviderID{
+				"org", "a1b2c3d4-0000-4000-8000-00000000000f", "site", uuid.MustParse(testInstanceID),
+			},
+		},
+		{name: "wrong prefix", providerID: "aws:///us-east-1a/i-0123456789", wantErr: true},
+		{name: "unescaped slash", providerID: "nvidia-carbide://my/org/tenant/site/" + testInstanceID, wantErr: true},
+		{name: "invalid escape", providerID: "nvidia-carbide://org%zz/tenant/site/" + testInstanceID, wantErr: true},
+		{name: "invalid instance ID", providerID: "nvidia-carbide://org/tenant/site/instance", wantErr: true},
+		{name: "empty org", providerID: "nvidia-carbide:///tenant/site/" + testInstanceID, wantErr: true},
+		{name: "empty tenant", providerID: "nvidia-carbide://org//site/" + testInstanceID, wantErr: true},
+		{name: "empty site", providerID: "nvidia-carbide://org/tenant//" + testInstanceID, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProviderID(tt.providerID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseProviderID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && *got != *tt.want {
+				t.Errorf("ParseProviderID() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	instanceID := uuid.MustParse(testInstanceID)
+	pid := &ProviderID{"Org", "Tenant", "Site", instanceID}
+
+	if !pid.Equal(&ProviderID{"org", "TENANT", "site", instanceID}) {
+		t.Error("expected provider IDs differing only in case to be equal")
+	}
+	if pid.Equal(&ProviderID{"org", "", "site", instanceID}) {
+		t.Error("expected legacy and tenant-aware provider IDs to differ")
+	}
+	if pid.Equal(&ProviderID{"org", "tenant", "site", uuid.New()}) {
+		t.Error("expected provider IDs with different instances to differ")
+	}
+	if pid.Equal(nil) || !(*ProviderID)(nil).Equal(nil) {
+		t.Error("unexpected nil comparison result")
+	}
+}
+
+func FuzzProviderIDRoundTrip(f *testing.F) {
+	f.Add("org", "tenant", "site", testInstanceID)
+	f.Add("my/org", "ten%ant", "site a", testInstanceID)
+	f.Add("org", "A1B2C3D4-0000-4000-8000-00000000000F", "\xff/", "00000000-0000-0000-0000-000000000001")
+
+	f.Fuzz(func(t *testing.T, org, tenant, site, instance string) {
+		instanceID, err := uuid.Parse(instance)
+		if err != nil {
+			return
+		}
+		pid, err := NewProviderID(org, tenant, site, instanceID)
+		if err != nil {
+			return
+		}
+
+		parsed, err := ParseProviderID(pid.String())
+		if err != nil {
+			t.Fatalf("ParseProviderID(%q) error = %v", pid.String(), err)
+		}
+		if *parsed != *pid {
+			t.Fatalf("ParseProviderID(%q) = %+v, want %+v", pid.String(), parsed, pid)
+		}
+	})
+}
+
+func FuzzLegacyProviderIDRoundTrip(f *testing.F) {
+	f.Add("org", "site", testInstanceID)
+	f.Add("my/org", "%2F", testInstanceID)
+
+	f.Fuzz(func(t *testing.T, org, site, instance string) {
+		instanceID, err := uuid.Parse(instance)
+		if err != nil || org == "" || site == "" {
+			return
+		}
+		pid := &ProviderID{OrgName: normalizeSegment(org), SiteName: normalizeSegment(site), InstanceID: instanceID}
+
+		parsed, err := ParseProviderID(pid.String())
+		if err != nil {
+			t.Fatalf("ParseProviderID(%q) error = %v", pid.String(), err)
+		}
+		if !parsed.IsLegacy() || *parsed != *pid {
+			t.Fatalf("ParseProviderID(%q) = %+v, want %+v", pid.String(), parsed, pid)
+		}
+	})
+}
+
+func FuzzParseProviderID(f *testing.F) {
+	f.Add("nvidia-carbide://org/tenant/site/" + testInstanceID)
+	f.Add("nvidia-carbide://org/site/" + testInstanceID)
+	f.Add("nvidia-carbide://org%2/tenant/site/" + testInstanceID)
+	f.Add("nvidia-carbide://org//site/" + testInstanceID)
+
+	f.Fuzz(func(t *testing.T, providerID string) {
+		pid, err := ParseProviderID(providerID)
+		if err != nil {
+			return
+		}
+		// Printing keeps the format of the parsed provider ID
+		if got, want := strings.Count(pid.String(), "/"), strings.Count(providerID, "/"); got != want {
+			t.Fatalf("ParseProviderID(%q).String() = %q, changed the number of segments", providerID, pid.String())
+		}
+		reparsed, err := ParseProviderID(pid.String())
+		if err != nil {
+			t.Fatalf("ParseProviderID(%q) error = %v", pid.String(), err)
+		}
+		if *reparsed != *pid {
+			t.Fatalf("ParseProviderID(%q) = %+v, want %+v", pid.String(), reparsed, pid)
+		}
+	})
+}
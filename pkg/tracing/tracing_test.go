@@ -0,0 +1,76 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"testing"
+)
+
+func TestExporterConfigured(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{
+			name: "no endpoint",
+			env:  map[string]string{},
+			want: false,
+		},
+		{
+			name: "generic endpoint",
+			env:  map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"},
+			want: true,
+		},
+		{
+			name: "traces endpoint",
+			env:  map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://collector:4318/v1/traces"},
+			want: true,
+		},
+		{
+			name: "sdk disabled",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318",
+				"OTEL_SDK_DISABLED":           "true",
+			},
+			want: false,
+		},
+		{
+			name: "exporter none",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318",
+				"OTEL_TRACES_EXPORTER":        "none",
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{
+				"OTEL_SDK_DISABLED", "OTEL_TRACES_EXPORTER",
+				"OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+			} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			if got := exporterConfigured(); got != tt.want {
+				t.Errorf("exporterConfigured() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
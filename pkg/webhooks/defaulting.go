@@ -0,0 +1,211 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+)
+
+// Keys of the provider defaults ConfigMap
+const (
+	// DefaultsSiteIDKey is the default siteId
+	DefaultsSiteIDKey = "siteId"
+	// DefaultsTenantIDKey is the default tenantId
+	DefaultsTenantIDKey = "tenantId"
+	// DefaultsVpcIDKey is the default vpcId
+	DefaultsVpcIDKey = "vpcId"
+	// DefaultsCredentialsSecretKey is the default credentials Secret as namespace/name
+	DefaultsCredentialsSecretKey = "credentialsSecret"
+	// DefaultsClusterIDKey is the cluster ID used when a Machine has no cluster label
+	DefaultsClusterIDKey = "clusterId"
+)
+
+const (
+	// ClusterIDInstanceLabel is the Carbide instance label naming the owning cluster
+	ClusterIDInstanceLabel = "openshift-cluster-id"
+	// MachineRoleInstanceLabel is the Carbide instance label naming the Machine role
+	MachineRoleInstanceLabel = "openshift-machine-role"
+
+	// clusterIDLabel and machineRoleLabel are the Machine API labels set by the installer
+	clusterIDLabel   = "machine.openshift.io/cluster-api-cluster"
+	machineRoleLabel = "machine.openshift.io/cluster-api-machine-role"
+)
+
+// ProviderSpecDefaulter fills missing provider spec fields from a cluster-level
+// ConfigMap and labels Carbide instances with their cluster and role
+type ProviderSpecDefaulter struct {
+	// Reader reads the defaults ConfigMap
+	Reader client.Reader
+
+	// ConfigMap is the provider defaults ConfigMap, if any
+	ConfigMap *client.ObjectKey
+
+	// CredentialsSecret is used when neither the provider spec nor the
+	// ConfigMap set a credentials Secret
+	CredentialsSecret *client.ObjectKey
+}
+
+// providerDefaults are the values filled into provider specs
+type providerDefaults struct {
+	siteID            string
+	tenantID          string
+	vpcID             string
+	clusterID         string
+	credentialsSecret *client.ObjectKey
+}
+
+// load reads the defaults ConfigMap. A missing ConfigMap provides no defaults.
+func (d *ProviderSpecDefaulter) load(ctx context.Context) (*providerDefaults, error) {
+	defaults := &providerDefaults{credentialsSecret: d.CredentialsSecret}
+	if d.ConfigMap == nil {
+		return defaults, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := d.Reader.Get(ctx, *d.ConfigMap, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return defaults, nil
+		}
+		return nil, fmt.Errorf("failed to get provider defaults configmap %s: %w", d.ConfigMap, err)
+	}
+
+	defaults.siteID = configMap.Data[DefaultsSiteIDKey]
+	defaults.tenantID = configMap.Data[DefaultsTenantIDKey]
+	defaults.vpcID = configMap.Data[DefaultsVpcIDKey]
+	defaults.clusterID = configMap.Data[DefaultsClusterIDKey]
+	if value := configMap.Data[DefaultsCredentialsSecretKey]; value != "" {
+		key, err := machine.ParseCredentialsSecretKey(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in provider defaults configmap %s: %w",
+				DefaultsCredentialsSecretKey, d.ConfigMap, err)
+		}
+		defaults.credentialsSecret = &key
+	}
+	return defaults, nil
+}
+
+// defaultProviderSpecValue sets missing fields of an embedded provider spec.
+// It works on the decoded JSON so that fields it does not know are kept.
+func (d *ProviderSpecDefaulter) defaultProviderSpecValue(
+	ctx context.Context, value *runtime.RawExtension, objLabels map[string]string,
+) error {
+	if value == nil || len(value.Raw) == 0 {
+		return nil
+	}
+
+	// Decoding errors are left to the validating webhook to report
+	if own, err := machine.IsOwnProviderSpec(value); err != nil || !own {
+		return nil
+	}
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal(value.Raw, &spec); err != nil {
+		return nil
+	}
+
+	defaults, err := d.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	setDefault(spec, "siteId", defaults.siteID)
+	setDefault(spec, "tenantId", defaults.tenantID)
+	setDefault(spec, "vpcId", defaults.vpcID)
+
+	ref, _ := spec["credentialsSecret"].(map[string]interface{})
+	if name, _ := ref["name"].(string); name == "" && defaults.credentialsSecret != nil {
+		spec["credentialsSecret"] = map[string]interface{}{
+			"name":      defaults.credentialsSecret.Name,
+			"namespace": defaults.credentialsSecret.Namespace,
+		}
+	}
+
+	clusterID := objLabels[clusterIDLabel]
+	if clusterID == "" {
+		clusterID = defaults.clusterID
+	}
+	instanceLabels, _ := spec["labels"].(map[string]interface{})
+	if instanceLabels == nil {
+		instanceLabels = map[string]interface{}{}
+	}
+	setDefault(instanceLabels, ClusterIDInstanceLabel, clusterID)
+	setDefault(instanceLabels, MachineRoleInstanceLabel, objLabels[machineRoleLabel])
+	if len(instanceLabels) > 0 {
+		spec["labels"] = instanceLabels
+	}
+
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode provider spec: %w", err)
+	}
+	value.Raw = raw
+	value.Object = nil
+	return nil
+}
+
+// setDefault sets key to value when value is not empty and key is unset or empty
+func setDefault(m map[string]interface{}, key, value string) {
+	if value == "" {
+		return
+	}
+	if current, _ := m[key].(string); current == "" {
+		m[key] = value
+	}
+}
+
+// MachineDefaulter defaults the provider spec of Machines
+type MachineDefaulter struct {
+	*ProviderSpecDefaulter
+}
+
+var _ admission.CustomDefaulter = &MachineDefaulter{}
+
+// Default fills missing provider spec fields of a Machine
+func (d *MachineDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	machineObj, ok := obj.(*machinev1beta1.Machine)
+	if !ok {
+		return fmt.Errorf("expected a Machine, got %T", obj)
+	}
+	return d.defaultProviderSpecValue(ctx, machineObj.Spec.ProviderSpec.Value, machineObj.Labels)
+}
+
+// MachineSetDefaulter defaults the template provider spec of MachineSets
+type MachineSetDefaulter struct {
+	*ProviderSpecDefaulter
+}
+
+var _ admission.CustomDefaulter = &MachineSetDefaulter{}
+
+// Default fills missing template provider spec fields of a MachineSet
+func (d *MachineSetDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	machineSet, ok := obj.(*machinev1beta1.MachineSet)
+	if !ok {
+		return fmt.Errorf("expected a MachineSet, got %T", obj)
+	}
+	return d.defaultProviderSpecValue(ctx, machineSet.Spec.Template.Spec.ProviderSpec.Value,
+		machineSet.Spec.Template.Labels)
+}
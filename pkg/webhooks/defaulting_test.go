@@ -0,0 +1,124 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1beta1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1beta1"
+)
+
+func TestMachineDefaulter(t *testing.T) {
+	configMapKey := client.ObjectKey{Namespace: "openshift-machine-api", Name: "nvidia-carbide-provider-defaults"}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: configMapKey.Name, Namespace: configMapKey.Namespace},
+		Data: map[string]string{
+			DefaultsSiteIDKey:            "550e8400-e29b-41d4-a716-446655440000",
+			DefaultsTenantIDKey:          "660e8400-e29b-41d4-a716-446655440001",
+			DefaultsVpcIDKey:             "770e8400-e29b-41d4-a716-446655440002",
+			DefaultsCredentialsSecretKey: "openshift-machine-api/nvidia-carbide-credentials",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	defaulter := &MachineDefaulter{&ProviderSpecDefaulter{
+		Reader:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+		ConfigMap: &configMapKey,
+	}}
+
+	machineObj := &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				clusterIDLabel:   "my-cluster-x7k2p",
+				machineRoleLabel: "worker",
+			},
+		},
+		Spec: machinev1beta1.MachineSpec{ProviderSpec: machinev1beta1.ProviderSpec{
+			Value: &runtime.RawExtension{Raw: []byte(`{
+				"kind": "NvidiaCarbideMachineProviderSpec",
+				"vpcId": "aa0e8400-e29b-41d4-a716-446655440005",
+				"subnetId": "880e8400-e29b-41d4-a716-446655440003",
+				"labels": {"environment": "production"}
+			}`)},
+		}},
+	}
+
+	if err := defaulter.Default(context.Background(), machineObj); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+
+	spec := &v1beta1.NvidiaCarbideMachineProviderSpec{}
+	if err := json.Unmarshal(machineObj.Spec.ProviderSpec.Value.Raw, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if spec.SiteID != configMap.Data[DefaultsSiteIDKey] || spec.TenantID != configMap.Data[DefaultsTenantIDKey] {
+		t.Errorf("expected site and tenant defaults, got %q and %q", spec.SiteID, spec.TenantID)
+	}
+	if spec.VpcID != "aa0e8400-e29b-41d4-a716-446655440005" {
+		t.Errorf("expected the explicit vpcId to be kept, got %q", spec.VpcID)
+	}
+	wantSecret := v1beta1.CredentialsSecretReference{
+		Name:      "nvidia-carbide-credentials",
+		Namespace: "openshift-machine-api",
+	}
+	if spec.CredentialsSecret != wantSecret {
+		t.Errorf("expected credentials secret %+v, got %+v", wantSecret, spec.CredentialsSecret)
+	}
+	wantLabels := map[string]string{
+		"environment":            "production",
+		ClusterIDInstanceLabel:   "my-cluster-x7k2p",
+		MachineRoleInstanceLabel: "worker",
+	}
+	for key, value := range wantLabels {
+		if spec.Labels[key] != value {
+			t.Errorf("expected instance label %s=%s, got %q", key, value, spec.Labels[key])
+		}
+	}
+}
+
+func TestMachineDefaulter_OtherProvider(t *testing.T) {
+	for _, raw := range []string{
+		`{"kind":"AWSMachineProviderConfig","instanceType":"m5.large"}`,
+		`{"apiVersion":"example.com/v1","kind":"NvidiaCarbideMachineProviderSpec"}`,
+	} {
+		machineObj := &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{clusterIDLabel: "my-cluster"}},
+			Spec: machinev1beta1.MachineSpec{ProviderSpec: machinev1beta1.ProviderSpec{
+				Value: &runtime.RawExtension{Raw: []byte(raw)},
+			}},
+		}
+
+		defaulter := &MachineDefaulter{&ProviderSpecDefaulter{}}
+		if err := defaulter.Default(context.Background(), machineObj); err != nil {
+			t.Fatalf("Default() error = %v", err)
+		}
+		if got := string(machineObj.Spec.ProviderSpec.Value.Raw); got != raw {
+			t.Errorf("expected other provider specs to be left alone, got %s", got)
+		}
+	}
+}
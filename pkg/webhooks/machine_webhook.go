@@ -0,0 +1,296 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	kjson "sigs.k8s.io/json"
+
+	"github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/actuators/machine"
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	v1beta1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1beta1"
+)
+
+var (
+	machineGroupKind    = machinev1beta1.GroupVersion.WithKind("Machine").GroupKind()
+	machineSetGroupKind = machinev1beta1.GroupVersion.WithKind("MachineSet").GroupKind()
+)
+
+// MachineValidator validates the provider spec of Machines
+type MachineValidator struct{}
+
+var _ admission.CustomValidator = &MachineValidator{}
+
+// ValidateCreate validates a new Machine
+func (v *MachineValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	machineObj, ok := obj.(*machinev1beta1.Machine)
+	if !ok {
+		return nil, fmt.Errorf("expected a Machine, got %T", obj)
+	}
+	return nil, toInvalid(machineGroupKind, machineObj.Name,
+		validateProviderSpecValue(machineObj.Spec.ProviderSpec.Value, field.NewPath("spec", "providerSpec", "value")))
+}
+
+// ValidateUpdate validates a Machine whose provider spec changed
+func (v *MachineValidator) ValidateUpdate(
+	_ context.Context, oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	oldMachine, ok := oldObj.(*machinev1beta1.Machine)
+	if !ok {
+		return nil, fmt.Errorf("expected a Machine, got %T", oldObj)
+	}
+	newMachine, ok := newObj.(*machinev1beta1.Machine)
+	if !ok {
+		return nil, fmt.Errorf("expected a Machine, got %T", newObj)
+	}
+
+	// Let finalizers and labels be updated on Machines created before this
+	// webhook, or being deleted, as long as the provider spec is untouched
+	if !newMachine.GetDeletionTimestamp().IsZero() ||
+		rawEqual(oldMachine.Spec.ProviderSpec.Value, newMachine.Spec.ProviderSpec.Value) {
+		return nil, nil
+	}
+
+	fldPath := field.NewPath("spec", "providerSpec", "value")
+	errs := validateProviderSpecValue(newMachine.Spec.ProviderSpec.Value, fldPath)
+	if len(errs) == 0 && machineProvisioned(oldMachine) {
+		errs = validateImmutableFields(oldMachine.Spec.ProviderSpec.Value, newMachine.Spec.ProviderSpec.Value, fldPath)
+	}
+	return nil, toInvalid(machineGroupKind, newMachine.Name, errs)
+}
+
+// machineProvisioned reports whether a Machine has an instance, through its
+// provider ID or the instance ID in its provider status
+func machineProvisioned(machineObj *machinev1beta1.Machine) bool {
+	if machineObj.Spec.ProviderID != nil && *machineObj.Spec.ProviderID != "" {
+		return true
+	}
+	if machineObj.Status.ProviderStatus == nil || len(machineObj.Status.ProviderStatus.Raw) == 0 {
+		return false
+	}
+	providerStatus := &v1.NvidiaCarbideMachineProviderStatus{}
+	if err := json.Unmarshal(machineObj.Status.ProviderStatus.Raw, providerStatus); err != nil {
+		return false
+	}
+	return providerStatus.InstanceID != nil
+}
+
+// validateImmutableFields rejects changes to provider spec fields that cannot
+// be applied to a provisioned instance
+func validateImmutableFields(oldValue, newValue *runtime.RawExtension, fldPath *field.Path) field.ErrorList {
+	if oldValue == nil {
+		return nil
+	}
+	oldSpec, err := machine.ProviderSpecFromRawExtension(oldValue)
+	if err != nil {
+		// Nothing valid to compare with
+		return nil
+	}
+	newSpec, err := machine.ProviderSpecFromRawExtension(newValue)
+	if err != nil {
+		return nil
+	}
+
+	changed := machine.ImmutableFieldChanges(oldSpec, newSpec)
+	if len(changed) == 0 {
+		return nil
+	}
+	immutable := machine.ImmutableFields
+	if isV1beta1(newValue) {
+		changed = v1beta1FieldNames(changed, oldSpec, newSpec)
+		immutable = v1beta1ImmutableFields
+	}
+
+	message := machine.ImmutableFieldsMessage(changed, immutable)
+	errs := make(field.ErrorList, 0, len(changed))
+	for _, name := range changed {
+		errs = append(errs, field.Forbidden(fldPath.Child(name), message))
+	}
+	return errs
+}
+
+// ValidateDelete allows all deletions
+func (v *MachineValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// MachineSetValidator validates the template provider spec of MachineSets
+type MachineSetValidator struct{}
+
+var _ admission.CustomValidator = &MachineSetValidator{}
+
+// ValidateCreate validates a new MachineSet
+func (v *MachineSetValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	machineSet, ok := obj.(*machinev1beta1.MachineSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a MachineSet, got %T", obj)
+	}
+	return nil, toInvalid(machineSetGroupKind, machineSet.Name,
+		validateProviderSpecValue(machineSet.Spec.Template.Spec.ProviderSpec.Value, machineSetProviderSpecPath()))
+}
+
+// ValidateUpdate validates a MachineSet whose template provider spec changed
+func (v *MachineSetValidator) ValidateUpdate(
+	_ context.Context, oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	oldMachineSet, ok := oldObj.(*machinev1beta1.MachineSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a MachineSet, got %T", oldObj)
+	}
+	newMachineSet, ok := newObj.(*machinev1beta1.MachineSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a MachineSet, got %T", newObj)
+	}
+
+	if !newMachineSet.GetDeletionTimestamp().IsZero() ||
+		rawEqual(oldMachineSet.Spec.Template.Spec.ProviderSpec.Value, newMachineSet.Spec.Template.Spec.ProviderSpec.Value) {
+		return nil, nil
+	}
+
+	return nil, toInvalid(machineSetGroupKind, newMachineSet.Name,
+		validateProviderSpecValue(newMachineSet.Spec.Template.Spec.ProviderSpec.Value, machineSetProviderSpecPath()))
+}
+
+// ValidateDelete allows all deletions
+func (v *MachineSetValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func machineSetProviderSpecPath() *field.Path {
+	return field.NewPath("spec", "template", "spec", "providerSpec", "value")
+}
+
+// validateProviderSpecValue decodes and validates an embedded provider spec.
+// Provider specs of other kinds or API groups are left to their own provider.
+// Field names are matched case-sensitively, as the provider decodes them.
+func validateProviderSpecValue(value *runtime.RawExtension, fldPath *field.Path) field.ErrorList {
+	if value == nil || len(value.Raw) == 0 {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+
+	own, err := machine.IsOwnProviderSpec(value)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, "", fmt.Sprintf("failed to decode provider spec: %v", err))}
+	}
+	if !own {
+		return nil
+	}
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(value.Raw, &typeMeta); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, "", fmt.Sprintf("failed to decode provider spec: %v", err))}
+	}
+
+	switch typeMeta.APIVersion {
+	case v1.SchemeGroupVersion.String():
+		providerSpec := &v1.NvidiaCarbideMachineProviderSpec{}
+		if err := kjson.UnmarshalCaseSensitivePreserveInts(value.Raw, providerSpec); err != nil {
+			return field.ErrorList{field.Invalid(fldPath, "", fmt.Sprintf("failed to decode provider spec: %v", err))}
+		}
+		return ValidateV1ProviderSpec(providerSpec, fldPath)
+	case "", v1beta1.SchemeGroupVersion.String():
+		providerSpec := &v1beta1.NvidiaCarbideMachineProviderSpec{}
+		if err := kjson.UnmarshalCaseSensitivePreserveInts(value.Raw, providerSpec); err != nil {
+			return field.ErrorList{field.Invalid(fldPath, "", fmt.Sprintf("failed to decode provider spec: %v", err))}
+		}
+		return ValidateProviderSpec(providerSpec, fldPath)
+	default:
+		return field.ErrorList{field.NotSupported(fldPath.Child("apiVersion"), typeMeta.APIVersion,
+			[]string{v1.SchemeGroupVersion.String(), v1beta1.SchemeGroupVersion.String()})}
+	}
+}
+
+// isV1beta1 reports whether a raw provider spec is a v1beta1 one
+func isV1beta1(value *runtime.RawExtension) bool {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(value.Raw, &typeMeta); err != nil {
+		return false
+	}
+	return typeMeta.APIVersion == "" || typeMeta.APIVersion == v1beta1.SchemeGroupVersion.String()
+}
+
+// v1beta1ImmutableFields are the immutable fields by their v1beta1 JSON name
+var v1beta1ImmutableFields = []string{
+	"siteId", "tenantId", "vpcId", "subnetId", "additionalSubnetIds", "instanceTypeId", "machineId",
+}
+
+// v1beta1FieldNames replaces the v1 interfaces field by the v1beta1 subnet
+// fields that changed
+func v1beta1FieldNames(changed []string, oldSpec, newSpec *v1.NvidiaCarbideMachineProviderSpec) []string {
+	names := make([]string, 0, len(changed)+1)
+	for _, name := range changed {
+		if name != "interfaces" {
+			names = append(names, name)
+			continue
+		}
+		if oldSpec.PrimarySubnetID() != newSpec.PrimarySubnetID() {
+			names = append(names, "subnetId")
+		}
+		if len(oldSpec.Interfaces) == 0 || len(newSpec.Interfaces) == 0 ||
+			!slices.Equal(oldSpec.Interfaces[1:], newSpec.Interfaces[1:]) {
+			names = append(names, "additionalSubnetIds")
+		}
+	}
+	return names
+}
+
+func rawEqual(a, b *runtime.RawExtension) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return bytes.Equal(a.Raw, b.Raw)
+}
+
+func toInvalid(gk schema.GroupKind, name string, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(gk, name, errs)
+}
+
+// SetupWebhooks registers the Machine and MachineSet webhooks with the manager
+func SetupWebhooks(mgr ctrl.Manager, defaulter *ProviderSpecDefaulter) error {
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&machinev1beta1.Machine{}).
+		WithDefaulter(&MachineDefaulter{defaulter}).
+		WithValidator(&MachineValidator{}).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to set up Machine webhook: %w", err)
+	}
+
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&machinev1beta1.MachineSet{}).
+		WithDefaulter(&MachineSetDefaulter{defaulter}).
+		WithValidator(&MachineSetValidator{}).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to set up MachineSet webhook: %w", err)
+	}
+
+	return nil
+}
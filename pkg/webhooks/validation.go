@@ -0,0 +1,168 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	v1beta1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1beta1"
+)
+
+// MaxUserDataSize is the largest user data accepted in a provider spec
+const MaxUserDataSize = 64 * 1024
+
+// ValidateProviderSpec checks a v1beta1 provider spec before it reaches
+// NVIDIA Carbide
+func ValidateProviderSpec(spec *v1beta1.NvidiaCarbideMachineProviderSpec, fldPath *field.Path) field.ErrorList {
+	v1Spec := &v1.NvidiaCarbideMachineProviderSpec{}
+	spec.ConvertTo(v1Spec)
+	return validateProviderSpec(v1Spec, fldPath, specPaths{
+		subnetID: func(i int) *field.Path {
+			if i == 0 {
+				return fldPath.Child("subnetId")
+			}
+			return fldPath.Child("additionalSubnetIds").Index(i - 1).Child("subnetId")
+		},
+		userData: fldPath.Child("userData"),
+	})
+}
+
+// ValidateV1ProviderSpec checks a v1 provider spec before it reaches NVIDIA
+// Carbide
+func ValidateV1ProviderSpec(spec *v1.NvidiaCarbideMachineProviderSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if len(spec.Interfaces) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("interfaces"), "at least one interface is required"))
+	}
+
+	bootPath := fldPath.Child("bootSource")
+	if spec.BootSource.OperatingSystemID != "" && spec.BootSource.IPXEScript != "" {
+		errs = append(errs, field.Forbidden(bootPath.Child("ipxeScript"),
+			"operatingSystemId and ipxeScript are mutually exclusive"))
+	}
+	errs = append(errs, validateOptionalUUID(spec.BootSource.OperatingSystemID,
+		bootPath.Child("operatingSystemId"))...)
+
+	return append(errs, validateProviderSpec(spec, fldPath, specPaths{
+		subnetID: func(i int) *field.Path {
+			return fldPath.Child("interfaces").Index(i).Child("subnetId")
+		},
+		userData: bootPath.Child("userData"),
+	})...)
+}
+
+// specPaths locates the fields whose path differs between provider spec
+// versions
+type specPaths struct {
+	// subnetID returns the path of the subnet of interface i
+	subnetID func(i int) *field.Path
+	userData *field.Path
+}
+
+func validateProviderSpec(
+	spec *v1.NvidiaCarbideMachineProviderSpec, fldPath *field.Path, paths specPaths,
+) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, validateRequiredUUID(spec.SiteID, fldPath.Child("siteId"))...)
+	errs = append(errs, validateRequiredUUID(spec.TenantID, fldPath.Child("tenantId"))...)
+	errs = append(errs, validateRequiredUUID(spec.VpcID, fldPath.Child("vpcId"))...)
+	if len(spec.Interfaces) > 0 {
+		errs = append(errs, validateRequiredUUID(spec.Interfaces[0].SubnetID, paths.subnetID(0))...)
+	}
+
+	switch {
+	case spec.InstanceTypeID != "" && spec.MachineID != "":
+		errs = append(errs, field.Forbidden(fldPath.Child("machineId"),
+			"instanceTypeId and machineId are mutually exclusive"))
+	case spec.InstanceTypeID == "" && spec.MachineID == "":
+		errs = append(errs, field.Required(fldPath.Child("instanceTypeId"),
+			"one of instanceTypeId or machineId must be set"))
+	}
+	errs = append(errs, validateOptionalUUID(spec.InstanceTypeID, fldPath.Child("instanceTypeId"))...)
+	errs = append(errs, validateOptionalUUID(spec.MachineID, fldPath.Child("machineId"))...)
+
+	subnets := map[string]bool{spec.PrimarySubnetID(): true}
+	for i := 1; i < len(spec.Interfaces); i++ {
+		subnetID := spec.Interfaces[i].SubnetID
+		errs = append(errs, validateRequiredUUID(subnetID, paths.subnetID(i))...)
+		if subnetID != "" && subnets[subnetID] {
+			errs = append(errs, field.Duplicate(paths.subnetID(i), subnetID))
+		}
+		subnets[subnetID] = true
+	}
+
+	for i, id := range spec.SSHKeyGroupIDs {
+		errs = append(errs, validateRequiredUUID(id, fldPath.Child("sshKeyGroupIds").Index(i))...)
+	}
+
+	if len(spec.BootSource.UserData) > MaxUserDataSize {
+		errs = append(errs, field.TooLong(paths.userData, "", MaxUserDataSize))
+	}
+
+	errs = append(errs, validateCredentialsSecret(spec.CredentialsSecret, fldPath.Child("credentialsSecret"))...)
+
+	return errs
+}
+
+// validateCredentialsSecret checks the shape of a credentials Secret
+// reference. An empty reference selects the manager default.
+func validateCredentialsSecret(ref *corev1.SecretReference, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if ref == nil || ref.Name == "" {
+		if ref != nil && ref.Namespace != "" {
+			errs = append(errs, field.Required(fldPath.Child("name"), "name is required when namespace is set"))
+		}
+		return errs
+	}
+
+	for _, msg := range validation.IsDNS1123Subdomain(ref.Name) {
+		errs = append(errs, field.Invalid(fldPath.Child("name"), ref.Name, msg))
+	}
+	if ref.Namespace != "" {
+		for _, msg := range validation.IsDNS1123Label(ref.Namespace) {
+			errs = append(errs, field.Invalid(fldPath.Child("namespace"), ref.Namespace, msg))
+		}
+	}
+	return errs
+}
+
+func validateRequiredUUID(value string, fldPath *field.Path) field.ErrorList {
+	if value == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	return validateOptionalUUID(value, fldPath)
+}
+
+func validateOptionalUUID(value string, fldPath *field.Path) field.ErrorList {
+	if value == "" {
+		return nil
+	}
+	// Only accept the canonical form, which is what NVIDIA Carbide returns
+	if len(value) != 36 || uuid.Validate(value) != nil {
+		return field.ErrorList{field.Invalid(fldPath, value,
+			fmt.Sprintf("must be a UUID such as %q", "550e8400-e29b-41d4-a716-446655440000"))}
+	}
+	return nil
+}
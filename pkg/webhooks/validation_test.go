@@ -0,0 +1,300 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	v1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1"
+	v1beta1 "github.com/fabiendupont/machine-api-provider-nvidia-carbide/pkg/apis/nvidiacarbideprovider/v1beta1"
+)
+
+func validProviderSpec() *v1beta1.NvidiaCarbideMachineProviderSpec {
+	return &v1beta1.NvidiaCarbideMachineProviderSpec{
+		SiteID:         "550e8400-e29b-41d4-a716-446655440000",
+		TenantID:       "660e8400-e29b-41d4-a716-446655440001",
+		VpcID:          "770e8400-e29b-41d4-a716-446655440002",
+		SubnetID:       "880e8400-e29b-41d4-a716-446655440003",
+		InstanceTypeID: "990e8400-e29b-41d4-a716-446655440004",
+	}
+}
+
+func TestValidateProviderSpec(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutate     func(*v1beta1.NvidiaCarbideMachineProviderSpec)
+		wantFields []string
+	}{
+		{
+			name:   "valid",
+			mutate: func(*v1beta1.NvidiaCarbideMachineProviderSpec) {},
+		},
+		{
+			name: "missing required fields",
+			mutate: func(s *v1beta1.NvidiaCarbideMachineProviderSpec) {
+				s.SiteID = ""
+				s.VpcID = ""
+			},
+			wantFields: []string{"spec.siteId", "spec.vpcId"},
+		},
+		{
+			name: "malformed UUIDs",
+			mutate: func(s *v1beta1.NvidiaCarbideMachineProviderSpec) {
+				s.SubnetID = "880e8400-e29b-41d4-a716"
+				s.TenantID = "{660e8400-e29b-41d4-a716-446655440001}"
+				s.SSHKeyGroupIDs = []string{"not-a-uuid"}
+			},
+			wantFields: []string{"spec.tenantId", "spec.subnetId", "spec.sshKeyGroupIds[0]"},
+		},
+		{
+			name: "instance type and machine both set",
+			mutate: func(s *v1beta1.NvidiaCarbideMachineProviderSpec) {
+				s.MachineID = "aa0e8400-e29b-41d4-a716-446655440005"
+			},
+			wantFields: []string{"spec.machineId"},
+		},
+		{
+			name: "neither instance type nor machine set",
+			mutate: func(s *v1beta1.NvidiaCarbideMachineProviderSpec) {
+				s.InstanceTypeID = ""
+			},
+			wantFields: []string{"spec.instanceTypeId"},
+		},
+		{
+			name: "duplicate additional subnet",
+			mutate: func(s *v1beta1.NvidiaCarbideMachineProviderSpec) {
+				s.AdditionalSubnetIDs = []v1beta1.AdditionalSubnet{{SubnetID: s.SubnetID}}
+			},
+			wantFields: []string{"spec.additionalSubnetIds[0].subnetId"},
+		},
+		{
+			name: "credentials secret without name",
+			mutate: func(s *v1beta1.NvidiaCarbideMachineProviderSpec) {
+				s.CredentialsSecret = v1beta1.CredentialsSecretReference{Namespace: "openshift-machine-api"}
+			},
+			wantFields: []string{"spec.credentialsSecret.name"},
+		},
+		{
+			name: "credentials secret with invalid names",
+			mutate: func(s *v1beta1.NvidiaCarbideMachineProviderSpec) {
+				s.CredentialsSecret = v1beta1.CredentialsSecretReference{Name: "Creds_1", Namespace: "a.b"}
+			},
+			wantFields: []string{"spec.credentialsSecret.name", "spec.credentialsSecret.namespace"},
+		},
+		{
+			name: "user data too large",
+			mutate: func(s *v1beta1.NvidiaCarbideMachineProviderSpec) {
+				s.UserData = strings.Repeat("x", MaxUserDataSize+1)
+			},
+			wantFields: []string{"spec.userData"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := validProviderSpec()
+			tt.mutate(spec)
+
+			errs := ValidateProviderSpec(spec, field.NewPath("spec"))
+			var gotFields []string
+			for _, err := range errs {
+				gotFields = append(gotFields, err.Field)
+			}
+			if strings.Join(gotFields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("expected errors on %v, got %v", tt.wantFields, errs)
+			}
+		})
+	}
+}
+
+func TestValidateV1ProviderSpec(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutate     func(*v1.NvidiaCarbideMachineProviderSpec)
+		wantFields []string
+	}{
+		{
+			name:   "valid",
+			mutate: func(*v1.NvidiaCarbideMachineProviderSpec) {},
+		},
+		{
+			name: "no interface",
+			mutate: func(s *v1.NvidiaCarbideMachineProviderSpec) {
+				s.Interfaces = nil
+			},
+			wantFields: []string{"spec.interfaces"},
+		},
+		{
+			name: "duplicate interface subnet",
+			mutate: func(s *v1.NvidiaCarbideMachineProviderSpec) {
+				s.Interfaces = append(s.Interfaces, s.Interfaces[0])
+			},
+			wantFields: []string{"spec.interfaces[1].subnetId"},
+		},
+		{
+			name: "operating system and iPXE script both set",
+			mutate: func(s *v1.NvidiaCarbideMachineProviderSpec) {
+				s.BootSource.OperatingSystemID = "not-a-uuid"
+				s.BootSource.IPXEScript = "#!ipxe"
+			},
+			wantFields: []string{"spec.bootSource.ipxeScript", "spec.bootSource.operatingSystemId"},
+		},
+		{
+			name: "user data too large",
+			mutate: func(s *v1.NvidiaCarbideMachineProviderSpec) {
+				s.BootSource.UserData = strings.Repeat("x", MaxUserDataSize+1)
+			},
+			wantFields: []string{"spec.bootSource.userData"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &v1.NvidiaCarbideMachineProviderSpec{}
+			validProviderSpec().ConvertTo(spec)
+			tt.mutate(spec)
+
+			errs := ValidateV1ProviderSpec(spec, field.NewPath("spec"))
+			var gotFields []string
+			for _, err := range errs {
+				gotFields = append(gotFields, err.Field)
+			}
+			if strings.Join(gotFields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("expected errors on %v, got %v", tt.wantFields, errs)
+			}
+		})
+	}
+}
+
+func TestMachineValidator(t *testing.T) {
+	newMachine := func(raw string) *machinev1beta1.Machine {
+		return &machinev1beta1.Machine{
+			Spec: machinev1beta1.MachineSpec{ProviderSpec: machinev1beta1.ProviderSpec{
+				Value: &runtime.RawExtension{Raw: []byte(raw)},
+			}},
+		}
+	}
+	invalid := newMachine(`{"kind":"NvidiaCarbideMachineProviderSpec","siteId":"bad"}`)
+	validator := &MachineValidator{}
+
+	_, err := validator.ValidateCreate(context.Background(), invalid)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected an Invalid error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "spec.providerSpec.value.siteId") {
+		t.Errorf("expected the error to name the field path, got %v", err)
+	}
+
+	unsupported := newMachine(`{"apiVersion":"nvidiacarbideprovider.infrastructure.cluster.x-k8s.io/v2"}`)
+	if _, err := validator.ValidateCreate(context.Background(), unsupported); !apierrors.IsInvalid(err) {
+		t.Errorf("expected unsupported versions to be rejected, got %v", err)
+	}
+
+	// Field names are case-sensitive, as the provider decodes them
+	raw, _ := json.Marshal(validProviderSpec())
+	miscased := newMachine(strings.Replace(string(raw), `"siteId"`, `"siteID"`, 1))
+	_, err = validator.ValidateCreate(context.Background(), miscased)
+	if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), "spec.providerSpec.value.siteId") {
+		t.Errorf("expected siteID not to be taken for siteId, got %v", err)
+	}
+
+	// Provider specs of other providers are ignored
+	other := newMachine(`{"kind":"AWSMachineProviderConfig"}`)
+	if _, err := validator.ValidateCreate(context.Background(), other); err != nil {
+		t.Errorf("expected other provider specs to be ignored, got %v", err)
+	}
+	otherGroup := newMachine(`{"apiVersion":"example.com/v1","kind":"NvidiaCarbideMachineProviderSpec"}`)
+	if _, err := validator.ValidateCreate(context.Background(), otherGroup); err != nil {
+		t.Errorf("expected provider specs of other API groups to be ignored, got %v", err)
+	}
+
+	// Updates that leave an invalid provider spec untouched are allowed
+	updated := invalid.DeepCopy()
+	updated.Finalizers = []string{"machine.openshift.io/nvidia-carbide"}
+	if _, err := validator.ValidateUpdate(context.Background(), invalid, updated); err != nil {
+		t.Errorf("expected metadata-only update to be allowed, got %v", err)
+	}
+}
+
+func TestMachineValidator_ImmutableFields(t *testing.T) {
+	spec := validProviderSpec()
+	oldRaw, _ := json.Marshal(spec)
+	spec.SiteID = "aa0e8400-e29b-41d4-a716-446655440005"
+	spec.InstanceTypeID = "bb0e8400-e29b-41d4-a716-446655440006"
+	spec.UserData = "#cloud-config"
+	newRaw, _ := json.Marshal(spec)
+
+	providerID := "nvidia-carbide://org/tenant/site/instance"
+	oldMachine := &machinev1beta1.Machine{
+		Spec: machinev1beta1.MachineSpec{ProviderSpec: machinev1beta1.ProviderSpec{
+			Value: &runtime.RawExtension{Raw: oldRaw},
+		}},
+	}
+	newMachine := oldMachine.DeepCopy()
+	newMachine.Spec.ProviderSpec.Value = &runtime.RawExtension{Raw: newRaw}
+	validator := &MachineValidator{}
+
+	// Not provisioned yet, the change is allowed
+	if _, err := validator.ValidateUpdate(context.Background(), oldMachine, newMachine); err != nil {
+		t.Fatalf("expected changes before provisioning to be allowed, got %v", err)
+	}
+
+	oldMachine.Spec.ProviderID = &providerID
+	newMachine.Spec.ProviderID = &providerID
+	_, err := validator.ValidateUpdate(context.Background(), oldMachine, newMachine)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected an Invalid error, got %v", err)
+	}
+	for _, want := range []string{
+		"spec.providerSpec.value.siteId",
+		"spec.providerSpec.value.instanceTypeId",
+		"replace the Machine",
+		"immutable fields: siteId, tenantId, vpcId, subnetId, additionalSubnetIds, instanceTypeId, machineId",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to contain %q, got %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "userData") {
+		t.Errorf("expected userData to stay mutable, got %v", err)
+	}
+}
+
+func TestV1beta1FieldNames(t *testing.T) {
+	oldSpec := &v1.NvidiaCarbideMachineProviderSpec{Interfaces: []v1.NetworkInterface{{SubnetID: "a"}}}
+	newSpec := &v1.NvidiaCarbideMachineProviderSpec{
+		Interfaces: []v1.NetworkInterface{{SubnetID: "a"}, {SubnetID: "b"}},
+	}
+	got := v1beta1FieldNames([]string{"siteId", "interfaces"}, oldSpec, newSpec)
+	if strings.Join(got, ",") != "siteId,additionalSubnetIds" {
+		t.Errorf("expected siteId and additionalSubnetIds, got %v", got)
+	}
+
+	newSpec.Interfaces = []v1.NetworkInterface{{SubnetID: "c"}}
+	got = v1beta1FieldNames([]string{"interfaces"}, oldSpec, newSpec)
+	if strings.Join(got, ",") != "subnetId" {
+		t.Errorf("expected subnetId, got %v", got)
+	}
+}
@@ -113,8 +113,11 @@ type mockNvidiaCarbideClient struct {
 	createInstanceFunc func(
 		ctx context.Context, org string, req bmm.InstanceCreateRequest,
 	) (*bmm.Instance, *http.Response, error)
-	getInstanceFunc    func(ctx context.Context, org string, instanceId string) (*bmm.Instance, *http.Response, error)
-	deleteInstanceFunc func(ctx context.Context, org string, instanceId string) (*http.Response, error)
+	getInstanceFunc     func(ctx context.Context, org string, instanceId string) (*bmm.Instance, *http.Response, error)
+	deleteInstanceFunc  func(ctx context.Context, org string, instanceId string) (*http.Response, error)
+	getInstanceTypeFunc func(
+		ctx context.Context, org string, instanceTypeId string,
+	) (*bmm.InstanceType, *http.Response, error)
 }
 
 func (m *mockNvidiaCarbideClient) CreateInstance(
@@ -152,6 +155,53 @@ func (m *mockNvidiaCarbideClient) DeleteInstance(
 	return mockHTTPResponse(204), nil
 }
 
+func (m *mockNvidiaCarbideClient) GetInstanceType(
+	ctx context.Context, org string, instanceTypeId string,
+) (*bmm.InstanceType, *http.Response, error) {
+	if m.getInstanceTypeFunc != nil {
+		return m.getInstanceTypeFunc(ctx, org, instanceTypeId)
+	}
+	return &bmm.InstanceType{
+		Id: &instanceTypeId,
+	}, mockHTTPResponse(200), nil
+}
+
+func (m *mockNvidiaCarbideClient) GetSite(
+	ctx context.Context, org string, siteId string,
+) (*bmm.Site, *http.Response, error) {
+	return &bmm.Site{Id: &siteId}, mockHTTPResponse(200), nil
+}
+
+func (m *mockNvidiaCarbideClient) GetVpc(
+	ctx context.Context, org string, vpcId string,
+) (*bmm.VPC, *http.Response, error) {
+	return &bmm.VPC{Id: &vpcId}, mockHTTPResponse(200), nil
+}
+
+func (m *mockNvidiaCarbideClient) GetSubnet(
+	ctx context.Context, org string, subnetId string,
+) (*bmm.Subnet, *http.Response, error) {
+	return &bmm.Subnet{Id: &subnetId}, mockHTTPResponse(200), nil
+}
+
+func (m *mockNvidiaCarbideClient) GetSSHKeyGroup(
+	ctx context.Context, org string, sshKeyGroupId string,
+) (*bmm.SshKeyGroup, *http.Response, error) {
+	return &bmm.SshKeyGroup{Id: &sshKeyGroupId}, mockHTTPResponse(200), nil
+}
+
+func (m *mockNvidiaCarbideClient) RebootInstance(
+	ctx context.Context, org string, instanceId string,
+) (*http.Response, error) {
+	return mockHTTPResponse(200), nil
+}
+
+func (m *mockNvidiaCarbideClient) UpdateInstance(
+	ctx context.Context, org string, instanceId string, req bmm.InstanceUpdateRequest,
+) (*bmm.Instance, *http.Response, error) {
+	return &bmm.Instance{Id: &instanceId}, mockHTTPResponse(200), nil
+}
+
 var _ = Describe("Machine Actuator Integration", func() {
 	var (
 		namespace *corev1.Namespace